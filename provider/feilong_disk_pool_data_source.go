@@ -0,0 +1,226 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"golang.org/x/exp/maps"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FeilongDiskPoolDataSource{}
+
+func NewFeilongDiskPoolDataSource() datasource.DataSource {
+	return &FeilongDiskPoolDataSource{}
+}
+
+// FeilongDiskPoolDataSource defines the data source implementation.
+type FeilongDiskPoolDataSource struct {
+	Client *feilong.Client
+}
+
+// FeilongDiskPoolDataSourceModel describes the data source data model.
+type FeilongDiskPoolDataSourceModel struct {
+	Pool		types.String	`tfsdk:"pool"`
+	DiskTotal	types.Int64	`tfsdk:"disk_total"`
+	DiskUsed	types.Int64	`tfsdk:"disk_used"`
+	DiskAvailable	types.Int64	`tfsdk:"disk_available"`
+	Volumes		[]FeilongDiskPoolDataSourceVolume `tfsdk:"volumes"`
+	FreeExtents	[]FeilongDiskPoolDataSourceExtent `tfsdk:"free_extents"`
+}
+
+// FeilongDiskPoolDataSourceVolume describes one volume of the disk pool.
+type FeilongDiskPoolDataSourceVolume struct {
+	VolumeName	types.String	`tfsdk:"volume_name"`
+	VolumeType	types.String	`tfsdk:"volume_type"`
+	VolumeSize	types.String	`tfsdk:"volume_size"`
+}
+
+// FeilongDiskPoolDataSourceExtent describes one free extent of the disk pool.
+type FeilongDiskPoolDataSourceExtent struct {
+	VolumeName	types.String	`tfsdk:"volume_name"`
+	DeviceType	types.String	`tfsdk:"device_type"`
+	StartCylinder	types.String	`tfsdk:"start_cylinder"`
+	FreeSize	types.Int64	`tfsdk:"free_size"`
+	DASDGroup	types.String	`tfsdk:"dasd_group"`
+	RegionName	types.String	`tfsdk:"region_name"`
+}
+
+func (pool *FeilongDiskPoolDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_disk_pool"
+}
+
+func (pool *FeilongDiskPoolDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong disk pool data source",
+
+		Attributes: map[string]schema.Attribute {
+			"pool": schema.StringAttribute {
+				MarkdownDescription:	"Name of the disk pool, defaults to the disk pool of the z/VM cloud connector",
+				Optional:		true,
+			},
+			"disk_total": schema.Int64Attribute {
+				MarkdownDescription:	"Total disk size in gigabytes",
+				Computed:		true,
+			},
+			"disk_used": schema.Int64Attribute {
+				MarkdownDescription:	"Used disk size in gigabytes",
+				Computed:		true,
+			},
+			"disk_available": schema.Int64Attribute {
+				MarkdownDescription:	"Available disk size in gigabytes",
+				Computed:		true,
+			},
+			"volumes": schema.ListNestedAttribute {
+				MarkdownDescription:	"Volumes of the disk pool",
+				Computed:		true,
+				NestedObject:		schema.NestedAttributeObject {
+					Attributes: map[string]schema.Attribute {
+						"volume_name": schema.StringAttribute {
+							MarkdownDescription:	"Name of the volume",
+							Computed:		true,
+						},
+						"volume_type": schema.StringAttribute {
+							MarkdownDescription:	"Device type of the volume, e.g. 3390-09",
+							Computed:		true,
+						},
+						"volume_size": schema.StringAttribute {
+							MarkdownDescription:	"Size of the volume",
+							Computed:		true,
+						},
+					},
+				},
+			},
+			"free_extents": schema.ListNestedAttribute {
+				MarkdownDescription:	"Free extents of the disk pool",
+				Computed:		true,
+				NestedObject:		schema.NestedAttributeObject {
+					Attributes: map[string]schema.Attribute {
+						"volume_name": schema.StringAttribute {
+							MarkdownDescription:	"Name of the volume",
+							Computed:		true,
+						},
+						"device_type": schema.StringAttribute {
+							MarkdownDescription:	"Device type of the volume",
+							Computed:		true,
+						},
+						"start_cylinder": schema.StringAttribute {
+							MarkdownDescription:	"First cylinder of the extent",
+							Computed:		true,
+						},
+						"free_size": schema.Int64Attribute {
+							MarkdownDescription:	"Size of the extent in cylinders",
+							Computed:		true,
+						},
+						"dasd_group": schema.StringAttribute {
+							MarkdownDescription:	"DASD group of the extent",
+							Computed:		true,
+						},
+						"region_name": schema.StringAttribute {
+							MarkdownDescription:	"Region name of the extent",
+							Computed:		true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (pool *FeilongDiskPoolDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	pool.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (pool *FeilongDiskPoolDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeilongDiskPoolDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := pool.Client
+	var poolName *string
+	if !data.Pool.IsNull() {
+		name := data.Pool.ValueString()
+		poolName = &name
+	}
+
+	// Obtain the capacity of the disk pool
+	info, err := client.GetHostDiskPoolInfo(poolName)
+	if err != nil {
+		resp.Diagnostics.AddError("Disk Pool Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	data.DiskTotal = types.Int64Value(int64(info.Output.DiskTotal))
+	data.DiskUsed = types.Int64Value(int64(info.Output.DiskUsed))
+	data.DiskAvailable = types.Int64Value(int64(info.Output.DiskAvailable))
+
+	// Obtain the volumes of the disk pool
+	volumeNames, err := client.GetHostDiskPoolVolumeNames(poolName)
+	if err != nil {
+		resp.Diagnostics.AddError("Disk Pool Volumes Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	data.Volumes = []FeilongDiskPoolDataSourceVolume {}
+	for _, volumeName := range strings.Fields(volumeNames.Output.DiskPoolVolumes) {
+		volumeInfo, err := client.GetHostVolumeInfo(volumeName)
+		if err != nil {
+			resp.Diagnostics.AddError("Volume Querying Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+		data.Volumes = append(data.Volumes, FeilongDiskPoolDataSourceVolume {
+			VolumeName:	types.StringValue(volumeName),
+			VolumeType:	types.StringValue(volumeInfo.Output.VolumeType),
+			VolumeSize:	types.StringValue(volumeInfo.Output.VolumeSize),
+		})
+	}
+
+	// Obtain the free extents of the disk pool
+	details, err := client.GetHostDiskPoolDetails(poolName)
+	if err != nil {
+		resp.Diagnostics.AddError("Disk Pool Details Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	data.FreeExtents = []FeilongDiskPoolDataSourceExtent {}
+	pools := maps.Keys(details.Output)
+	sort.Strings(pools)
+	for _, p := range pools {
+		for _, extent := range details.Output[p] {
+			data.FreeExtents = append(data.FreeExtents, FeilongDiskPoolDataSourceExtent {
+				VolumeName:	types.StringValue(extent.VolumeName),
+				DeviceType:	types.StringValue(extent.DeviceType),
+				StartCylinder:	types.StringValue(extent.StartCylinder),
+				FreeSize:	types.Int64Value(int64(extent.FreeSize)),
+				DASDGroup:	types.StringValue(extent.DASDGroup),
+				RegionName:	types.StringValue(extent.RegionName),
+			})
+		}
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read a Feilong disk pool data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,577 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &FeilongFCPTemplate{}
+var _ resource.ResourceWithImportState = &FeilongFCPTemplate{}
+var _ resource.ResourceWithModifyPlan = &FeilongFCPTemplate{}
+
+func NewFeilongFCPTemplate() resource.Resource {
+	return &FeilongFCPTemplate{}
+}
+
+// FeilongFCPTemplate defines the resource implementation.
+type FeilongFCPTemplate struct {
+	Client *feilong.Client
+}
+
+// FeilongFCPTemplateModel describes the resource data model.
+type FeilongFCPTemplateModel struct {
+	Id		types.String	`tfsdk:"id"`
+	Name		types.String	`tfsdk:"name"`
+	Description	types.String	`tfsdk:"description"`
+	FCPDevices	types.String	`tfsdk:"fcp_devices"`
+	HostDefault	types.Bool	`tfsdk:"host_default"`
+	StorageProviders types.List	`tfsdk:"storage_providers"`
+	MinFCPPathsCount types.Int64	`tfsdk:"min_fcp_paths_count"`
+	Statistics	types.Map	`tfsdk:"statistics"`
+}
+
+// FeilongFCPTemplateStatisticsModel describes the usage statistics of one path.
+type FeilongFCPTemplateStatisticsModel struct {
+	Total		types.String	`tfsdk:"total"`
+	TotalCount	types.Map	`tfsdk:"total_count"`
+	Available	types.String	`tfsdk:"available"`
+	AvailableCount	types.Map	`tfsdk:"available_count"`
+	Allocated	types.String	`tfsdk:"allocated"`
+	ReserveOnly	types.String	`tfsdk:"reserve_only"`
+	ConnectionOnly	types.String	`tfsdk:"connection_only"`
+	AllocatedButFree types.String	`tfsdk:"allocated_but_free"`
+	NotFound	types.String	`tfsdk:"not_found"`
+	Offline		types.String	`tfsdk:"offline"`
+}
+
+var fcpTemplateStatisticsType = types.ObjectType {
+	AttrTypes: map[string]attr.Type {
+		"total":		types.StringType,
+		"total_count":		types.MapType { ElemType: types.Int64Type },
+		"available":		types.StringType,
+		"available_count":	types.MapType { ElemType: types.Int64Type },
+		"allocated":		types.StringType,
+		"reserve_only":		types.StringType,
+		"connection_only":	types.StringType,
+		"allocated_but_free":	types.StringType,
+		"not_found":		types.StringType,
+		"offline":		types.StringType,
+	},
+}
+
+func (template *FeilongFCPTemplate) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_fcp_template"
+}
+
+func (template *FeilongFCPTemplate) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong FCP device template resource",
+
+		Attributes: map[string]schema.Attribute {
+			"id": schema.StringAttribute {
+				MarkdownDescription:	"Identifier of the FCP template",
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute {
+				MarkdownDescription:	"Name of the FCP template",
+				Required:		true,
+			},
+			"description": schema.StringAttribute {
+				MarkdownDescription:	"Description of the FCP template",
+				Optional:		true,
+			},
+			"fcp_devices": schema.StringAttribute {
+				MarkdownDescription:	"FCP device ranges, one set per path separated by semicolons, e.g. 1A00-1A03;1B00-1B03",
+				Optional:		true,
+			},
+			"host_default": schema.BoolAttribute {
+				MarkdownDescription:	"Whether this is the default FCP template of the host",
+				Optional:		true,
+			},
+			"storage_providers": schema.ListAttribute {
+				MarkdownDescription:	"Storage providers for which this is the default FCP template",
+				ElementType:		types.StringType,
+				Optional:		true,
+			},
+			"min_fcp_paths_count": schema.Int64Attribute {
+				MarkdownDescription:	"Minimal number of paths to allocate FCP devices from",
+				Optional:		true,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.Int64 {
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"statistics": schema.MapNestedAttribute {
+				MarkdownDescription:	"Usage statistics of the FCP devices, per path",
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.Map {
+					mapplanmodifier.UseStateForUnknown(),
+				},
+				NestedObject:		schema.NestedAttributeObject {
+					Attributes: map[string]schema.Attribute {
+						"total": schema.StringAttribute {
+							MarkdownDescription:	"All FCP devices",
+							Computed:		true,
+						},
+						"total_count": schema.MapAttribute {
+							MarkdownDescription:	"Count of all FCP devices, per channel",
+							ElementType:		types.Int64Type,
+							Computed:		true,
+						},
+						"available": schema.StringAttribute {
+							MarkdownDescription:	"FCP devices available for allocation",
+							Computed:		true,
+						},
+						"available_count": schema.MapAttribute {
+							MarkdownDescription:	"Count of FCP devices available for allocation, per channel",
+							ElementType:		types.Int64Type,
+							Computed:		true,
+						},
+						"allocated": schema.StringAttribute {
+							MarkdownDescription:	"FCP devices allocated to guests",
+							Computed:		true,
+						},
+						"reserve_only": schema.StringAttribute {
+							MarkdownDescription:	"FCP devices reserved but without connections",
+							Computed:		true,
+						},
+						"connection_only": schema.StringAttribute {
+							MarkdownDescription:	"FCP devices with connections but not reserved",
+							Computed:		true,
+						},
+						"allocated_but_free": schema.StringAttribute {
+							MarkdownDescription:	"FCP devices allocated but free on z/VM",
+							Computed:		true,
+						},
+						"not_found": schema.StringAttribute {
+							MarkdownDescription:	"FCP devices not found on z/VM",
+							Computed:		true,
+						},
+						"offline": schema.StringAttribute {
+							MarkdownDescription:	"FCP devices offline on z/VM",
+							Computed:		true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (template *FeilongFCPTemplate) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	template.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (template *FeilongFCPTemplate) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan FeilongFCPTemplateModel
+	var state FeilongFCPTemplateModel
+
+	// Nothing to do on creation or destruction
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The statistics are kept from the state, unless the FCP devices change
+	if !plan.FCPDevices.Equal(state.FCPDevices) || !plan.MinFCPPathsCount.Equal(state.MinFCPPathsCount) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("statistics"), types.MapUnknown(fcpTemplateStatisticsType))...)
+	}
+
+	// The z/VM connector ignores an empty list of storage providers,
+	// so they cannot all be removed in place
+	if plan.StorageProviders.IsUnknown() || len(state.StorageProviders.Elements()) == 0 {
+		return
+	}
+	if len(plan.StorageProviders.Elements()) == 0 {
+		resp.Diagnostics.AddError("Unsupported Change", fmt.Sprintf("Cannot remove all storage providers of FCP template \"%s\", keep at least one or replace the template", state.Name.ValueString()))
+	}
+}
+
+func (template *FeilongFCPTemplate) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FeilongFCPTemplateModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create the FCP template
+	client := template.Client
+	hostDefault := data.HostDefault.ValueBool()
+	createParams := feilong.CreateFCPTemplateParams {
+		Name:		data.Name.ValueString(),
+		Description:	data.Description.ValueString(),
+		FCPDevices:	data.FCPDevices.ValueString(),
+	}
+	if !data.HostDefault.IsNull() {
+		createParams.HostDefault = &hostDefault
+	}
+	resp.Diagnostics.Append(data.StorageProviders.ElementsAs(ctx, &createParams.StorageProviders, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !data.MinFCPPathsCount.IsUnknown() {
+		createParams.MinFCPPathsCount = int(data.MinFCPPathsCount.ValueInt64())
+	}
+
+	result, err := client.CreateFCPTemplate(&createParams)
+	if err != nil {
+		resp.Diagnostics.AddError("Creation Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	data.Id = types.StringValue(result.Output.FCPTemplate.Id)
+
+	// Get computed values
+	found, diags := readFCPTemplate(ctx, client, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError("FCP Template Not Found Error", fmt.Sprintf("FCP template \"%s\" not listed after creation", data.Id.ValueString()))
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Created a Feilong FCP template resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (template *FeilongFCPTemplate) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FeilongFCPTemplateModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Obtain info about this FCP template
+	found, diags := readFCPTemplate(ctx, template.Client, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		// the FCP template was deleted outside of terraform
+		tflog.Info(ctx, "FCP template " + data.Id.ValueString() + " not found, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// CAVEATS:
+	//  - the FCP device ranges are only available as usage statistics
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read characteristics of Feilong FCP template resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (template *FeilongFCPTemplate) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data FeilongFCPTemplateModel
+	var state FeilongFCPTemplateModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Also read current state, for comparaison
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := template.Client
+	templateId := state.Id.ValueString()
+	data.Id = state.Id
+
+	// Only send the values that changed
+	editParams := feilong.EditFCPTemplateParams {}
+	changed := false
+	if !data.Name.Equal(state.Name) {
+		name := data.Name.ValueString()
+		editParams.Name = &name
+		changed = true
+	}
+	if !data.Description.Equal(state.Description) {
+		description := data.Description.ValueString()
+		editParams.Description = &description
+		changed = true
+	}
+	if !data.FCPDevices.Equal(state.FCPDevices) {
+		fcpDevices := data.FCPDevices.ValueString()
+		editParams.FCPDevices = &fcpDevices
+		changed = true
+	}
+	if !data.HostDefault.Equal(state.HostDefault) {
+		hostDefault := data.HostDefault.ValueBool()
+		editParams.HostDefault = &hostDefault
+		changed = true
+	}
+	if !data.StorageProviders.Equal(state.StorageProviders) {
+		editParams.DefaultSPList = []string {}
+		resp.Diagnostics.Append(data.StorageProviders.ElementsAs(ctx, &editParams.DefaultSPList, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		changed = true
+	}
+	if !data.MinFCPPathsCount.IsUnknown() && !data.MinFCPPathsCount.Equal(state.MinFCPPathsCount) {
+		minFCPPathsCount := int(data.MinFCPPathsCount.ValueInt64())
+		editParams.MinFCPPathsCount = &minFCPPathsCount
+		changed = true
+	}
+
+	// Edit the FCP template in place
+	if changed {
+		err := client.EditFCPTemplate(templateId, &editParams)
+		if err != nil {
+			resp.Diagnostics.AddError("FCP Template Edition Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+	}
+
+	// Get computed values
+	found, diags := readFCPTemplate(ctx, client, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError("FCP Template Not Found Error", fmt.Sprintf("FCP template \"%s\" not listed after edition", templateId))
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Updated Feilong FCP template resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (template *FeilongFCPTemplate) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FeilongFCPTemplateModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := template.Client
+	templateId := data.Id.ValueString()
+
+	// Delete the FCP template
+	err := client.DeleteFCPTemplate(templateId)
+	if err != nil {
+		resp.Diagnostics.AddError("Deletion Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Deleted the Feilong FCP template resource")
+}
+
+func (template *FeilongFCPTemplate) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// For internal use
+
+func readFCPTemplate(ctx context.Context, client *feilong.Client, data *FeilongFCPTemplateModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	templateId := data.Id.ValueString()
+	result, err := client.GetFCPTemplatesDetails([]string { templateId }, false, true, false)
+	if err != nil {
+		diags.AddError("FCP Template Querying Error", fmt.Sprintf("Got error: %s", err))
+		return false, diags
+	}
+	var details *feilong.GetFCPTemplateDetails
+	for i, t := range result.Output.FCPTemplates {
+		if t.Id == templateId {
+			details = &result.Output.FCPTemplates[i]
+			break
+		}
+	}
+	if details == nil {
+		return false, diags
+	}
+
+	// Read name and description
+	data.Name = types.StringValue(details.Name)
+	if data.Description.IsNull() && details.Description == "" {
+		tflog.Info(ctx, "Not replacing undeclared description with empty value")
+	} else {
+		data.Description = types.StringValue(details.Description)
+	}
+
+	// Read host default flag
+	hostDefault := details.HostDefault != nil && *details.HostDefault
+	if data.HostDefault.IsNull() && !hostDefault {
+		tflog.Info(ctx, "Not replacing undeclared host default flag with default value false")
+	} else {
+		data.HostDefault = types.BoolValue(hostDefault)
+	}
+
+	// Read storage providers
+	if data.StorageProviders.IsNull() && len(details.StorageProviders) == 0 {
+		tflog.Info(ctx, "Not replacing undeclared storage providers with empty list")
+	} else {
+		storageProviders, d := types.ListValueFrom(ctx, types.StringType, details.StorageProviders)
+		diags.Append(d...)
+		data.StorageProviders = storageProviders
+	}
+
+	// Read minimal number of paths
+	data.MinFCPPathsCount = types.Int64Value(int64(details.MinFCPPathsCount))
+
+	// Read usage statistics
+	statistics, d := fcpTemplateStatisticsValue(ctx, details.Statistics)
+	diags.Append(d...)
+	data.Statistics = statistics
+
+	return true, diags
+}
+
+func fcpTemplateStatisticsValue(ctx context.Context, fcpStatistics map[string]feilong.FCPTemplateStatistics) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	statistics := map[string]FeilongFCPTemplateStatisticsModel {}
+	for pathId, s := range fcpStatistics {
+		totalCount, d := types.MapValueFrom(ctx, types.Int64Type, s.TotalCount)
+		diags.Append(d...)
+		availableCount, d := types.MapValueFrom(ctx, types.Int64Type, s.AvailableCount)
+		diags.Append(d...)
+		statistics[pathId] = FeilongFCPTemplateStatisticsModel {
+			Total:		types.StringValue(s.Total),
+			TotalCount:	totalCount,
+			Available:	types.StringValue(s.Available),
+			AvailableCount:	availableCount,
+			Allocated:	types.StringValue(s.Allocated),
+			ReserveOnly:	types.StringValue(s.ReserveOnly),
+			ConnectionOnly:	types.StringValue(s.ConnectionOnly),
+			AllocatedButFree: types.StringValue(s.AllocatedButFree),
+			NotFound:	types.StringValue(s.NotFound),
+			Offline:	types.StringValue(s.Offline),
+		}
+	}
+	statisticsValue, d := types.MapValueFrom(ctx, fcpTemplateStatisticsType, statistics)
+	diags.Append(d...)
+	return statisticsValue, diags
+}
+
+// Usage of an FCP device, as reported in the raw details of an FCP template
+type fcpDeviceUsage struct {
+	FCP		string
+	AssignerId	string
+	Connections	int
+	Reserved	bool
+	WWPN		string
+}
+
+// Find the FCP devices of a template that are reserved for a guest or connected to it.
+// Raw entries are:
+//   [fcp_id, template_id, assigner_id, connections, reserved, wwpn_npiv, wwpn_phy, chpid, pchid, state, owner, tmpl_id]
+func guestFCPDevices(details *feilong.GetFCPTemplateDetails, userid string) []fcpDeviceUsage {
+	var devices []fcpDeviceUsage
+
+	pathIds := make([]string, 0, len(details.Raw))
+	for pathId := range details.Raw {
+		pathIds = append(pathIds, pathId)
+	}
+	sort.Strings(pathIds)
+
+	for _, pathId := range pathIds {
+		for _, entry := range details.Raw[pathId] {
+			if len(entry) < 7 || !strings.EqualFold(rawString(entry[2]), userid) {
+				continue
+			}
+			device := fcpDeviceUsage {
+				FCP:		rawString(entry[0]),
+				AssignerId:	rawString(entry[2]),
+				Connections:	rawInt(entry[3]),
+				Reserved:	rawInt(entry[4]) != 0,
+				WWPN:		rawString(entry[5]),
+			}
+			if device.WWPN == "" {
+				device.WWPN = rawString(entry[6])
+			}
+			if device.Reserved || device.Connections > 0 {
+				devices = append(devices, device)
+			}
+		}
+	}
+	return devices
+}
+
+func rawString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func rawInt(value interface{}) int {
+	switch v := value.(type) {
+		case float64:
+			return int(v)
+		case bool:
+			if v {
+				return 1
+			}
+		case string:
+			i, err := strconv.Atoi(v)
+			if err == nil {
+				return i
+			}
+	}
+	return 0
+}
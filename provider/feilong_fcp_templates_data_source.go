@@ -0,0 +1,299 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FeilongFCPTemplatesDataSource{}
+
+func NewFeilongFCPTemplatesDataSource() datasource.DataSource {
+	return &FeilongFCPTemplatesDataSource{}
+}
+
+// FeilongFCPTemplatesDataSource defines the data source implementation.
+type FeilongFCPTemplatesDataSource struct {
+	Client *feilong.Client
+}
+
+// FeilongFCPTemplatesDataSourceModel describes the data source data model.
+type FeilongFCPTemplatesDataSourceModel struct {
+	Ids		[]types.String	`tfsdk:"ids"`
+	UserId		types.String	`tfsdk:"userid"`
+	StorageProvider	types.String	`tfsdk:"storage_provider"`
+	HostDefault	types.Bool	`tfsdk:"host_default"`
+	Statistics	types.Bool	`tfsdk:"statistics"`
+	FCPTemplates	[]FeilongFCPTemplatesDataSourceTemplate `tfsdk:"fcp_templates"`
+}
+
+// FeilongFCPTemplatesDataSourceTemplate describes one FCP template of the data source.
+type FeilongFCPTemplatesDataSourceTemplate struct {
+	Id		types.String	`tfsdk:"id"`
+	Name		types.String	`tfsdk:"name"`
+	Description	types.String	`tfsdk:"description"`
+	HostDefault	types.Bool	`tfsdk:"host_default"`
+	StorageProviders []types.String	`tfsdk:"storage_providers"`
+	MinFCPPathsCount types.Int64	`tfsdk:"min_fcp_paths_count"`
+	CPCSerialNumber	types.String	`tfsdk:"cpc_serial_number"`
+	CPCName		types.String	`tfsdk:"cpc_name"`
+	LogicalPartition types.String	`tfsdk:"lpar"`
+	HypervisorHostname types.String	`tfsdk:"hypervisor_hostname"`
+	PhysicalChannelIds []types.String `tfsdk:"pchids"`
+	Statistics	types.Map	`tfsdk:"statistics"`
+}
+
+func (templates *FeilongFCPTemplatesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_fcp_templates"
+}
+
+func (templates *FeilongFCPTemplatesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong FCP device templates data source",
+
+		Attributes: map[string]schema.Attribute {
+			"ids": schema.ListAttribute {
+				MarkdownDescription:	"Identifiers of the FCP templates",
+				ElementType:		types.StringType,
+				Optional:		true,
+			},
+			"userid": schema.StringAttribute {
+				MarkdownDescription:	"System name for z/VM of a guest, to get only the FCP templates it uses",
+				Optional:		true,
+			},
+			"storage_provider": schema.StringAttribute {
+				MarkdownDescription:	"Storage provider, to get only its default FCP templates",
+				Optional:		true,
+			},
+			"host_default": schema.BoolAttribute {
+				MarkdownDescription:	"Whether to get only the default FCP template of the host",
+				Optional:		true,
+			},
+			"statistics": schema.BoolAttribute {
+				MarkdownDescription:	"Whether to look up the usage statistics of the FCP devices",
+				Optional:		true,
+			},
+			"fcp_templates": schema.ListNestedAttribute {
+				MarkdownDescription:	"Matching FCP templates",
+				Computed:		true,
+				NestedObject:		schema.NestedAttributeObject {
+					Attributes: map[string]schema.Attribute {
+						"id": schema.StringAttribute {
+							MarkdownDescription:	"Identifier of the FCP template",
+							Computed:		true,
+						},
+						"name": schema.StringAttribute {
+							MarkdownDescription:	"Name of the FCP template",
+							Computed:		true,
+						},
+						"description": schema.StringAttribute {
+							MarkdownDescription:	"Description of the FCP template",
+							Computed:		true,
+						},
+						"host_default": schema.BoolAttribute {
+							MarkdownDescription:	"Whether this is the default FCP template of the host",
+							Computed:		true,
+						},
+						"storage_providers": schema.ListAttribute {
+							MarkdownDescription:	"Storage providers using this FCP template by default",
+							ElementType:		types.StringType,
+							Computed:		true,
+						},
+						"min_fcp_paths_count": schema.Int64Attribute {
+							MarkdownDescription:	"Minimal number of paths",
+							Computed:		true,
+						},
+						"cpc_serial_number": schema.StringAttribute {
+							MarkdownDescription:	"Serial number of the central processor complex",
+							Computed:		true,
+						},
+						"cpc_name": schema.StringAttribute {
+							MarkdownDescription:	"Name of the central processor complex",
+							Computed:		true,
+						},
+						"lpar": schema.StringAttribute {
+							MarkdownDescription:	"Logical partition",
+							Computed:		true,
+						},
+						"hypervisor_hostname": schema.StringAttribute {
+							MarkdownDescription:	"Host name of the hypervisor",
+							Computed:		true,
+						},
+						"pchids": schema.ListAttribute {
+							MarkdownDescription:	"Physical channel identifiers",
+							ElementType:		types.StringType,
+							Computed:		true,
+						},
+						"statistics": schema.MapNestedAttribute {
+							MarkdownDescription:	"Usage statistics of the FCP devices, per path, if looked up",
+							Computed:		true,
+							NestedObject:		schema.NestedAttributeObject {
+								Attributes: map[string]schema.Attribute {
+									"total": schema.StringAttribute {
+										MarkdownDescription:	"All FCP devices",
+										Computed:		true,
+									},
+									"total_count": schema.MapAttribute {
+										MarkdownDescription:	"Count of all FCP devices, per channel",
+										ElementType:		types.Int64Type,
+										Computed:		true,
+									},
+									"available": schema.StringAttribute {
+										MarkdownDescription:	"FCP devices available for allocation",
+										Computed:		true,
+									},
+									"available_count": schema.MapAttribute {
+										MarkdownDescription:	"Count of FCP devices available for allocation, per channel",
+										ElementType:		types.Int64Type,
+										Computed:		true,
+									},
+									"allocated": schema.StringAttribute {
+										MarkdownDescription:	"FCP devices allocated to guests",
+										Computed:		true,
+									},
+									"reserve_only": schema.StringAttribute {
+										MarkdownDescription:	"FCP devices reserved but without connections",
+										Computed:		true,
+									},
+									"connection_only": schema.StringAttribute {
+										MarkdownDescription:	"FCP devices with connections but not reserved",
+										Computed:		true,
+									},
+									"allocated_but_free": schema.StringAttribute {
+										MarkdownDescription:	"FCP devices allocated but free on z/VM",
+										Computed:		true,
+									},
+									"not_found": schema.StringAttribute {
+										MarkdownDescription:	"FCP devices not found on z/VM",
+										Computed:		true,
+									},
+									"offline": schema.StringAttribute {
+										MarkdownDescription:	"FCP devices offline on z/VM",
+										Computed:		true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (templates *FeilongFCPTemplatesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	templates.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (templates *FeilongFCPTemplatesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeilongFCPTemplatesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Prepare the filters
+	var ids []string
+	for _, id := range data.Ids {
+		ids = append(ids, id.ValueString())
+	}
+	var assignerId, storageProvider, hostDefault *string
+	if !data.UserId.IsNull() {
+		userid := data.UserId.ValueString()
+		assignerId = &userid
+	}
+	if !data.StorageProvider.IsNull() {
+		sp := data.StorageProvider.ValueString()
+		storageProvider = &sp
+	}
+	if !data.HostDefault.IsNull() {
+		flag := strconv.FormatBool(data.HostDefault.ValueBool())
+		hostDefault = &flag
+	}
+
+	// Obtain the FCP templates
+	client := templates.Client
+	result, err := client.GetFCPTemplates(ids, assignerId, storageProvider, hostDefault)
+	if err != nil {
+		resp.Diagnostics.AddError("FCP Templates Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Obtain the usage statistics of these FCP templates
+	statistics := map[string]map[string]feilong.FCPTemplateStatistics {}
+	if data.Statistics.ValueBool() && len(result.Output.FCPTemplates) > 0 {
+		var foundIds []string
+		for _, t := range result.Output.FCPTemplates {
+			foundIds = append(foundIds, t.Id)
+		}
+		details, err := client.GetFCPTemplatesDetails(foundIds, false, true, false)
+		if err != nil {
+			resp.Diagnostics.AddError("FCP Template Details Querying Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+		for _, t := range details.Output.FCPTemplates {
+			statistics[t.Id] = t.Statistics
+		}
+	}
+
+	// Register the FCP templates
+	data.FCPTemplates = []FeilongFCPTemplatesDataSourceTemplate {}
+	for _, t := range result.Output.FCPTemplates {
+		template := FeilongFCPTemplatesDataSourceTemplate {
+			Id:			types.StringValue(t.Id),
+			Name:			types.StringValue(t.Name),
+			Description:		types.StringValue(t.Description),
+			HostDefault:		types.BoolValue(t.HostDefault != nil && *t.HostDefault),
+			StorageProviders:	[]types.String {},
+			MinFCPPathsCount:	types.Int64Value(int64(t.MinFCPPathsCount)),
+			CPCSerialNumber:	types.StringValue(t.CPCSerialNumber),
+			CPCName:		types.StringValue(t.CPCName),
+			LogicalPartition:	types.StringValue(t.LogicalPartition),
+			HypervisorHostname:	types.StringValue(t.HypervisorHostname),
+			PhysicalChannelIds:	[]types.String {},
+			Statistics:		types.MapNull(fcpTemplateStatisticsType),
+		}
+		for _, sp := range t.StorageProviderDefault {
+			template.StorageProviders = append(template.StorageProviders, types.StringValue(sp))
+		}
+		for _, pchid := range t.PhysicalChannelIds {
+			template.PhysicalChannelIds = append(template.PhysicalChannelIds, types.StringValue(pchid))
+		}
+		if s, found := statistics[t.Id]; found {
+			statisticsValue, d := fcpTemplateStatisticsValue(ctx, s)
+			resp.Diagnostics.Append(d...)
+			template.Statistics = statisticsValue
+		}
+		data.FCPTemplates = append(data.FCPTemplates, template)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read a Feilong FCP templates data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -13,6 +13,7 @@ import (
 	"errors"
 	"fmt"
 	"time"
+	"os"
 
 	// There is no replacement for the old resource.StateChangeConf
 	// (see https://discuss.hashicorp.com/t/terraform-plugin-framework-what-is-the-replacement-for-waitforstate-or-retrycontext/45538)
@@ -21,7 +22,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -61,6 +64,8 @@ type FeilongGuestModel struct {
 	AdapterAddress	types.String	`tfsdk:"adapter_address"`
 	VSwitch		types.String	`tfsdk:"vswitch"`
 	CloudinitParams	types.String	`tfsdk:"cloudinit_params"`
+	PowerState	types.String	`tfsdk:"power_state"`
+	ConsoleLogFile	types.String	`tfsdk:"console_log_file"`
 	MACAddress	types.String	`tfsdk:"mac_address"`
 	IPAddress	types.String	`tfsdk:"ip_address"`
 }
@@ -82,6 +87,9 @@ func (guest *FeilongGuest) Schema(ctx context.Context, req resource.SchemaReques
 				MarkdownDescription:	"System name for z/VM",
 				Optional:		true,
 				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"vcpus": schema.Int64Attribute {
 				MarkdownDescription:	"Virtual CPUs count",
@@ -143,6 +151,9 @@ func (guest *FeilongGuest) Schema(ctx context.Context, req resource.SchemaReques
 				MarkdownDescription:	"Desired MAC address of first interface",
 				Optional:		true,
 				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"vswitch": schema.StringAttribute {
 				MarkdownDescription:	"Name of virtual switch to connect to",
@@ -154,6 +165,16 @@ func (guest *FeilongGuest) Schema(ctx context.Context, req resource.SchemaReques
 				MarkdownDescription:	"Path to cloud-init parameters file",
 				Optional:		true,
 			},
+			"power_state": schema.StringAttribute {
+				MarkdownDescription:	"Desired power state (on, off, or paused)",
+				Optional:		true,
+				Computed:		true,
+				Default:		stringdefault.StaticString("on"),
+			},
+			"console_log_file": schema.StringAttribute {
+				MarkdownDescription:	"Local file where to save the console output if the deployment fails",
+				Optional:		true,
+			},
 			"mac_address": schema.StringAttribute {
 				MarkdownDescription:	"MAC address of first interface after deployment",
 				Computed:		true,
@@ -219,6 +240,12 @@ func (guest *FeilongGuest) Create(ctx context.Context, req resource.CreateReques
 	mac := data.MAC.ValueString()
 	vswitch := data.VSwitch.ValueString()
 	cloudinitParams := data.CloudinitParams.ValueString()
+	powerState := data.PowerState.ValueString()
+	err = checkPowerState(powerState)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Power State", fmt.Sprintf("Got error: %s", err))
+		return
+	}
 	localUser := guest.LocalUser
 
 	// Create the guest
@@ -282,24 +309,42 @@ func (guest *FeilongGuest) Create(ctx context.Context, req resource.CreateReques
 	}
 	err = client.DeployGuest(userid, &deployParams)
 	if err != nil {
-		resp.Diagnostics.AddError("Deployment Error", fmt.Sprintf("Got error: %s", err))
-		return
-	}
-
-	// Start the guest
-	err = client.StartGuest(userid)
-	if err != nil {
-		resp.Diagnostics.AddError("Startup Error", fmt.Sprintf("Got error: %s", err))
+		resp.Diagnostics.AddError("Deployment Error", consoleErrorDetail(ctx, client, userid, data.ConsoleLogFile.ValueString(), err))
 		return
 	}
 
-	// Wait until the guest gets an IP address
 	var macAddress string
 	var ipAddress string
-	err = waitForLease(ctx, client, userid, &macAddress, &ipAddress)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Waiting for an IP Address", fmt.Sprintf("Got error: %s", err))
-		return
+	if powerState == "off" {
+		// Leave the guest stopped, it cannot get an IP address
+		err = getAddresses(client, userid, &macAddress, &ipAddress)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Reading the IP Address", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+	} else {
+		// Start the guest
+		err = client.StartGuest(userid)
+		if err != nil {
+			resp.Diagnostics.AddError("Startup Error", consoleErrorDetail(ctx, client, userid, data.ConsoleLogFile.ValueString(), err))
+			return
+		}
+
+		// Wait until the guest gets an IP address
+		err = waitForLease(ctx, client, userid, &macAddress, &ipAddress)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Waiting for an IP Address", consoleErrorDetail(ctx, client, userid, data.ConsoleLogFile.ValueString(), err))
+			return
+		}
+
+		// Pause the guest if requested
+		if powerState == "paused" {
+			err = client.PauseGuest(userid)
+			if err != nil {
+				resp.Diagnostics.AddError("Pause Error", fmt.Sprintf("Got error: %s", err))
+				return
+			}
+		}
 	}
 	data.MACAddress = types.StringValue(macAddress)
 	data.IPAddress = types.StringValue(ipAddress)
@@ -334,10 +379,13 @@ func (guest *FeilongGuest) Read(ctx context.Context, req resource.ReadRequest, r
 	data.VCPUs = types.Int64Value(int64(guestInfo.Output.NumCPUs))
 
 	// Read memory
-	declaredMemory, err := convertToMegabytes(data.Memory.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Conversion Error", fmt.Sprintf("Got error: %s", err))
-		return
+	declaredMemory := 0
+	if !data.Memory.IsNull() {
+		declaredMemory, err = convertToMegabytes(data.Memory.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Conversion Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
 	}
 	obtainedMemory := guestInfo.Output.MaxMemKB / 1_024
 	if declaredMemory == obtainedMemory {
@@ -360,10 +408,13 @@ func (guest *FeilongGuest) Read(ctx context.Context, req resource.ReadRequest, r
 	firstMinidisk := minidisksInfo.Output.Minidisks[0]
 
 	// Read disk size
-	declaredDiskSize, err := convertToMegabytes(data.Disk.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Conversion Error", fmt.Sprintf("Got error: %s", err))
-		return
+	declaredDiskSize := 0
+	if !data.Disk.IsNull() {
+		declaredDiskSize, err = convertToMegabytes(data.Disk.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Conversion Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
 	}
 	if firstMinidisk.DeviceUnits != "Cylinders" {
 		resp.Diagnostics.AddError("Unknown Minidisk Unit Error", fmt.Sprintf("Got unit: %s", firstMinidisk.DeviceUnits))
@@ -393,12 +444,15 @@ func (guest *FeilongGuest) Read(ctx context.Context, req resource.ReadRequest, r
 	// Read virtual switch name
 	data.VSwitch = types.StringValue(firstAdapter.LANName)
 
-	// TODO: read adapter virtual device address
+	// Read adapter virtual device address
+	if firstAdapter.AdapterAddress != "" {
+		data.AdapterAddress = types.StringValue(firstAdapter.AdapterAddress)
+	}
 
 	// Read MAC address
 	declaredMAC := data.MAC.ValueString()
 	obtainedMAC := firstAdapter.MACAddress
-	if strings.ToLower(declaredMAC[8:]) == strings.ToLower(obtainedMAC[8:]) {
+	if sameMACAddress(declaredMAC, obtainedMAC) {
 		// do not overwrite a MAC address if last 3 hex bytes are the same
 		tflog.Info(ctx, "Not replacing MAC address " + declaredMAC + " with other MAC address with same last 3 hex bytes " + obtainedMAC)
 	} else {
@@ -408,13 +462,27 @@ func (guest *FeilongGuest) Read(ctx context.Context, req resource.ReadRequest, r
 	// Read IP address
 	declaredIPAddress := data.IPAddress.ValueString()
 	obtainedIPAddress := firstAdapter.IPAddress
-	if firstAdapter.IPVersion == "6" && strings.HasPrefix(obtainedIPAddress, "fe80:") {
+	if firstAdapter.IPVersion == "6" && strings.HasPrefix(obtainedIPAddress, "fe80:") && declaredIPAddress != "" {
 		// do not overwrite an IPv4 address with a link-local IPv6 address
 		tflog.Info(ctx, "Not replacing IP address " + declaredIPAddress + " with an IPv6 link-local address " + obtainedIPAddress)
 	} else {
 		data.IPAddress = types.StringValue(obtainedIPAddress)
 	}
 
+	// Read power state
+	powerStateResult, err := client.GetGuestPowerState(userid)
+	if err != nil {
+		resp.Diagnostics.AddError("Power State Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	obtainedPowerState := powerStateResult.Output
+	if data.PowerState.ValueString() == "paused" && obtainedPowerState == "on" {
+		// z/VM reports paused guests as running
+		tflog.Info(ctx, "Not replacing power state paused with equivalent value on")
+	} else {
+		data.PowerState = types.StringValue(obtainedPowerState)
+	}
+
 	// CAVEATS:
 	//  - the image used during the deployment cannot be determined after the deployment
 	//  - the cloud init image used during the deployment cannot be determined after the deployment
@@ -532,7 +600,10 @@ func (guest *FeilongGuest) Update(ctx context.Context, req resource.UpdateReques
 	// Address image changes
 	oldImage := state.Image.ValueString()
 	newImage := data.Image.ValueString()
-	if newImage != oldImage {
+	if state.Image.IsNull() {
+		// imported guest: adopt the declared image
+		tflog.Info(ctx, "Adopting image \"" + newImage + "\" for imported guest")
+	} else if newImage != oldImage {
 		// we could reapply a different image, but then all the user data would be lost
 		resp.Diagnostics.AddError("Immutable Value", fmt.Sprintf("Cannot change image used to install the system from \"%s\" to \"%s\"", oldImage, newImage))
 		return
@@ -549,7 +620,7 @@ func (guest *FeilongGuest) Update(ctx context.Context, req resource.UpdateReques
 	oldMac := state.MAC.ValueString()
 	newMac := data.MAC.ValueString()
 	if newMac != oldMac {
-		if strings.ToLower(newMac[8:]) != strings.ToLower(oldMac[8:]) {
+		if !sameMACAddress(newMac, oldMac) {
 			// we could delete the network interface and create a new one, but then it would not be same interface anymore
 			resp.Diagnostics.AddError("Immutable Value", fmt.Sprintf("Cannot change MAC address of main interface from \"%s\" to \"%s\"", oldMac, newMac))
 			return
@@ -570,19 +641,61 @@ func (guest *FeilongGuest) Update(ctx context.Context, req resource.UpdateReques
 	// Address cloud-init parameter changes
 	oldCloudinitParams := state.CloudinitParams.ValueString()
 	newCloudinitParams := data.CloudinitParams.ValueString()
-	if newCloudinitParams != oldCloudinitParams {
+	if state.Image.IsNull() {
+		// imported guest: adopt the declared cloud-init parameters
+		tflog.Info(ctx, "Adopting cloud-init parameters \"" + newCloudinitParams + "\" for imported guest")
+	} else if newCloudinitParams != oldCloudinitParams {
 		// we could redeploy, but then all the user data would be lost
 		resp.Diagnostics.AddError("Immutable Value", fmt.Sprintf("Cannot change cloud-init parameters used to install the system from \"%s\" to \"%s\"", oldCloudinitParams, newCloudinitParams))
 		return
 	}
 
-	// Get computed values
+	// Address desired power state changes
 	var macAddress string
 	var ipAddress string
-	err := getAddresses(client, userid, &macAddress, &ipAddress)
-	if err != nil {
-		resp.Diagnostics.AddError("Error Reading the IP Address", fmt.Sprintf("Got error: %s", err))
-		return
+	oldPowerState := state.PowerState.ValueString()
+	newPowerState := data.PowerState.ValueString()
+	started := false
+	if newPowerState != oldPowerState {
+		err := checkPowerState(newPowerState)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Power State", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+		if oldPowerState == "off" {
+			// Start the guest and wait until it gets an IP address, like on creation,
+			// even if it is to be paused afterwards
+			err = client.StartGuest(userid)
+			if err != nil {
+				resp.Diagnostics.AddError("Startup Error", consoleErrorDetail(ctx, client, userid, data.ConsoleLogFile.ValueString(), err))
+				return
+			}
+			err = waitForLease(ctx, client, userid, &macAddress, &ipAddress)
+			if err != nil {
+				resp.Diagnostics.AddError("Error Waiting for an IP Address", consoleErrorDetail(ctx, client, userid, data.ConsoleLogFile.ValueString(), err))
+				return
+			}
+			started = true
+		}
+		if started && newPowerState == "paused" {
+			err = changePowerState(ctx, client, userid, "on", newPowerState)
+		} else if !started {
+			err = changePowerState(ctx, client, userid, oldPowerState, newPowerState)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Power State Change Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+		tflog.Info(ctx, "Changed power state from " + oldPowerState + " to " + newPowerState)
+	}
+
+	// Get computed values
+	if !started {
+		err := getAddresses(client, userid, &macAddress, &ipAddress)
+		if err != nil {
+			resp.Diagnostics.AddError("Error Reading the IP Address", fmt.Sprintf("Got error: %s", err))
+			return
+		}
 	}
 	data.MACAddress = types.StringValue(macAddress)
 	data.IPAddress = types.StringValue(ipAddress)
@@ -618,7 +731,13 @@ func (guest *FeilongGuest) Delete(ctx context.Context, req resource.DeleteReques
 }
 
 func (guest *FeilongGuest) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// The import identifier is the z/VM userid; Read does the rest.
+	// The image, OS version, cloud-init parameters and network settings used
+	// during the deployment cannot be determined: they are left unset and
+	// adopted from the configuration at next apply
+	userid := strings.ToUpper(req.ID)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("userid"), userid)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), strings.ToLower(userid))...)
 }
 
 // For internal use
@@ -650,6 +769,74 @@ func convertToMegabytes(sizeWithUnit string) (int, error) {
 	return size, nil
 }
 
+func checkPowerState(powerState string) error {
+	switch powerState {
+		case "on", "off", "paused":
+			return nil
+	}
+	return errors.New("Power state must be one of on off paused")
+}
+
+func changePowerState(ctx context.Context, client *feilong.Client, userid string, from string, to string) error {
+	// a paused guest must be resumed before anything else
+	if from == "paused" {
+		err := client.UnpauseGuest(userid)
+		if err != nil || to == "on" {
+			return err
+		}
+		from = "on"
+	}
+
+	switch to {
+		case "on":
+			return client.StartGuest(userid)
+		case "off":
+			err := client.SoftStopGuest(userid)
+			if err != nil {
+				tflog.Warn(ctx, "Soft stop of " + userid + " failed, stopping it forcibly: " + err.Error())
+				err = client.StopGuest(userid)
+			}
+			return err
+		case "paused":
+			// a stopped guest must be started first, see Update()
+			return client.PauseGuest(userid)
+	}
+	return nil
+}
+
+func sameMACAddress(mac1 string, mac2 string) bool {
+	// Feilong may change the first 3 bytes of a MAC address
+	if len(mac1) < 8 || len(mac2) < 8 {
+		return false
+	}
+	return strings.ToLower(mac1[8:]) == strings.ToLower(mac2[8:])
+}
+
+const consoleTailLength int = 20
+
+func consoleErrorDetail(ctx context.Context, client *feilong.Client, userid string, consoleLogFile string, err error) string {
+	detail := fmt.Sprintf("Got error: %s", err)
+
+	lines, consoleErr := getConsoleLines(client, userid)
+	if consoleErr != nil {
+		tflog.Warn(ctx, "Could not get console output of " + userid + ": " + consoleErr.Error())
+		return detail
+	}
+
+	// Save the full console output if requested
+	if consoleLogFile != "" {
+		writeErr := os.WriteFile(consoleLogFile, []byte(strings.Join(lines, "\n") + "\n"), 0600)
+		if writeErr != nil {
+			tflog.Warn(ctx, "Could not save console output of " + userid + ": " + writeErr.Error())
+		} else {
+			detail += "\n\nFull console output saved into " + consoleLogFile
+		}
+	}
+
+	detail += "\n\nLast lines of console output:\n" + strings.Join(tailLines(lines, consoleTailLength), "\n")
+	return detail
+}
+
 const waitingMsg string = "Still waiting for IP address"
 const obtainedMsg string = "IP address obtained"
 
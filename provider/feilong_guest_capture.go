@@ -0,0 +1,330 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	oldresource "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &FeilongGuestCapture{}
+var _ resource.ResourceWithModifyPlan = &FeilongGuestCapture{}
+
+func NewFeilongGuestCapture() resource.Resource {
+	return &FeilongGuestCapture{}
+}
+
+// FeilongGuestCapture defines the resource implementation.
+type FeilongGuestCapture struct {
+	Client *feilong.Client
+}
+
+// FeilongGuestCaptureModel describes the resource data model.
+type FeilongGuestCaptureModel struct {
+	Name		types.String	`tfsdk:"name"`
+	UserId		types.String	`tfsdk:"userid"`
+	ImageName	types.String	`tfsdk:"image_name"`
+	CaptureType	types.String	`tfsdk:"capture_type"`
+	CompressLevel	types.Int64	`tfsdk:"compress_level"`
+	MD5Sum		types.String	`tfsdk:"md5sum"`
+	ImageSizeInBytes types.Int64	`tfsdk:"image_size_in_bytes"`
+	DiskSizeUnits	types.String	`tfsdk:"disk_size_units"`
+	ImageOSDistro	types.String	`tfsdk:"image_os_distro"`
+}
+
+func (capture *FeilongGuestCapture) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_guest_capture"
+}
+
+func (capture *FeilongGuestCapture) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong guest capture resource",
+
+		Attributes: map[string]schema.Attribute {
+			"name": schema.StringAttribute {
+				MarkdownDescription:	"Arbitrary name for the resource",
+				Required:		true,
+			},
+			"userid": schema.StringAttribute {
+				MarkdownDescription:	"System name for z/VM of the guest to capture",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"image_name": schema.StringAttribute {
+				MarkdownDescription:	"Name of the captured image on the z/VM connector",
+				Optional:		true,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"capture_type": schema.StringAttribute {
+				MarkdownDescription:	"Which disks to capture (rootonly or alldisks)",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"compress_level": schema.Int64Attribute {
+				MarkdownDescription:	"Compression level of the image, from 1 to 9",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.Int64 {
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"md5sum": schema.StringAttribute {
+				MarkdownDescription:	"MD5 checksum of the captured image",
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"image_size_in_bytes": schema.Int64Attribute {
+				MarkdownDescription:	"Size of the captured image in bytes",
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.Int64 {
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"disk_size_units": schema.StringAttribute {
+				MarkdownDescription:	"Size of the root disk of the captured image, with units",
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"image_os_distro": schema.StringAttribute {
+				MarkdownDescription:	"Operating system distribution of the captured image",
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (capture *FeilongGuestCapture) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	capture.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (capture *FeilongGuestCapture) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan FeilongGuestCaptureModel
+
+	// Nothing to do on destruction
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The Feilong client omits a compression level of 0,
+	// so the z/VM connector would silently use its default one instead
+	if plan.CompressLevel.IsNull() || plan.CompressLevel.IsUnknown() {
+		return
+	}
+	compressLevel := plan.CompressLevel.ValueInt64()
+	if compressLevel < 1 || compressLevel > 9 {
+		resp.Diagnostics.AddError("Invalid Compression Level", fmt.Sprintf("Got compression level: %d, it must be from 1 to 9", compressLevel))
+	}
+}
+
+func (capture *FeilongGuestCapture) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FeilongGuestCaptureModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Compute computed fields
+	imageName := data.ImageName.ValueString()
+	if imageName == "" {
+		imageName = data.Name.ValueString()
+		data.ImageName = types.StringValue(imageName)
+	}
+
+	// Capture the guest
+	client := capture.Client
+	captureParams := feilong.CaptureGuestParams {
+		Image:		imageName,
+		CaptureType:	data.CaptureType.ValueString(),
+		CompressLevel:	int(data.CompressLevel.ValueInt64()),
+	}
+	err := client.CaptureGuest(data.UserId.ValueString(), &captureParams)
+	if err != nil {
+		resp.Diagnostics.AddError("Capture Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Wait until the image is listed
+	var image *feilong.ListImagesImage
+	err = waitForImage(ctx, client, imageName, &image)
+	if err != nil {
+		resp.Diagnostics.AddError("Error Waiting for the Image", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Get computed values
+	err = capture.setImageData(&data, image)
+	if err != nil {
+		resp.Diagnostics.AddError("Image Size Conversion Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Created a Feilong guest capture resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (capture *FeilongGuestCapture) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FeilongGuestCaptureModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Obtain info about the captured image
+	imageName := data.ImageName.ValueString()
+	image, err := findImage(capture.Client, imageName)
+	if err != nil {
+		resp.Diagnostics.AddError("Image Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	if image == nil {
+		// the image was deleted outside of terraform
+		tflog.Info(ctx, "Image " + imageName + " not found, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	err = capture.setImageData(&data, image)
+	if err != nil {
+		resp.Diagnostics.AddError("Image Size Conversion Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// CAVEATS:
+	//  - the capture type and the compression level cannot be determined after the capture
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read characteristics of Feilong guest capture resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (capture *FeilongGuestCapture) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data FeilongGuestCaptureModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// All attributes of the capture itself require a replacement,
+	// so only the resource name can change here
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Updated Feilong guest capture resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (capture *FeilongGuestCapture) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FeilongGuestCaptureModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete the captured image
+	err := capture.Client.DeleteImage(data.ImageName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Deletion Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Deleted the Feilong guest capture resource")
+}
+
+// For internal use
+
+func (capture *FeilongGuestCapture) setImageData(data *FeilongGuestCaptureModel, image *feilong.ListImagesImage) error {
+	size, err := strconv.ParseInt(image.ImageSizeInBytes, 10, 64)
+	if err != nil {
+		return err
+	}
+	data.MD5Sum = types.StringValue(image.MD5Sum)
+	data.ImageSizeInBytes = types.Int64Value(size)
+	data.DiskSizeUnits = types.StringValue(image.DiskSizeUnits)
+	data.ImageOSDistro = types.StringValue(image.ImageOSDistro)
+	return nil
+}
+
+const imageWaitingMsg string = "Still waiting for image"
+const imageListedMsg string = "Image listed"
+
+func waitForImage(ctx context.Context, client *feilong.Client, imageName string, image **feilong.ListImagesImage) error {
+	waitFunction := func() (interface{}, string, error) {
+		result, err := findImage(client, imageName)
+		if err != nil {
+			return false, "", err
+		}
+		if result == nil {
+			return false, imageWaitingMsg, nil
+		}
+		*image = result
+		return true, imageListedMsg, nil
+	}
+
+	stateConf := &oldresource.StateChangeConf {
+		Pending:	[]string { imageWaitingMsg },
+		Target:		[]string { imageListedMsg },
+		Refresh:	waitFunction,
+		Timeout:	10 * time.Minute,
+		MinTimeout:	3 * time.Second,
+		Delay:		5 * time.Second,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
@@ -0,0 +1,123 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FeilongGuestConsoleDataSource{}
+
+func NewFeilongGuestConsoleDataSource() datasource.DataSource {
+	return &FeilongGuestConsoleDataSource{}
+}
+
+// FeilongGuestConsoleDataSource defines the data source implementation.
+type FeilongGuestConsoleDataSource struct {
+	Client *feilong.Client
+}
+
+// FeilongGuestConsoleDataSourceModel describes the data source data model.
+type FeilongGuestConsoleDataSourceModel struct {
+	UserId		types.String	`tfsdk:"userid"`
+	TailLines	types.Int64	`tfsdk:"tail_lines"`
+	Output		types.String	`tfsdk:"output"`
+}
+
+func (console *FeilongGuestConsoleDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_guest_console"
+}
+
+func (console *FeilongGuestConsoleDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong guest console output data source",
+
+		Attributes: map[string]schema.Attribute {
+			"userid": schema.StringAttribute {
+				MarkdownDescription:	"System name for z/VM",
+				Required:		true,
+			},
+			"tail_lines": schema.Int64Attribute {
+				MarkdownDescription:	"Number of lines to keep from the end of the output, all if omitted",
+				Optional:		true,
+			},
+			"output": schema.StringAttribute {
+				MarkdownDescription:	"Console output of the guest",
+				Computed:		true,
+			},
+		},
+	}
+}
+
+func (console *FeilongGuestConsoleDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	console.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (console *FeilongGuestConsoleDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeilongGuestConsoleDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Obtain the console output
+	lines, err := getConsoleLines(console.Client, strings.ToUpper(data.UserId.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Console Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	if !data.TailLines.IsNull() {
+		lines = tailLines(lines, int(data.TailLines.ValueInt64()))
+	}
+	data.Output = types.StringValue(strings.Join(lines, "\n"))
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read a Feilong guest console data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// For internal use
+
+func getConsoleLines(client *feilong.Client, userid string) ([]string, error) {
+	result, err := client.GetGuestConsoleOutput(userid)
+	if err != nil {
+		return nil, err
+	}
+
+	// the output chunks may themselves contain several lines
+	text := strings.ReplaceAll(strings.Join(result.Output, "\n"), "\r", "")
+	return strings.Split(strings.TrimRight(text, "\n"), "\n"), nil
+}
+
+func tailLines(lines []string, count int) []string {
+	if count < 0 {
+		count = 0
+	}
+	if len(lines) > count {
+		return lines[len(lines) - count:]
+	}
+	return lines
+}
@@ -0,0 +1,237 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FeilongGuestDataSource{}
+
+func NewFeilongGuestDataSource() datasource.DataSource {
+	return &FeilongGuestDataSource{}
+}
+
+// FeilongGuestDataSource defines the data source implementation.
+type FeilongGuestDataSource struct {
+	Client *feilong.Client
+}
+
+// FeilongGuestDataSourceModel describes the data source data model.
+type FeilongGuestDataSourceModel struct {
+	UserId		types.String	`tfsdk:"userid"`
+	VCPUs		types.Int64	`tfsdk:"vcpus"`
+	OnlineVCPUs	types.Int64	`tfsdk:"online_vcpus"`
+	CPUTime		types.Int64	`tfsdk:"cpu_time"`
+	MaxMemory	types.String	`tfsdk:"max_memory"`
+	Memory		types.String	`tfsdk:"memory"`
+	PowerState	types.String	`tfsdk:"power_state"`
+	OSDistro	types.String	`tfsdk:"os_distro"`
+	KernelInfo	types.String	`tfsdk:"kernel_info"`
+	Adapters	[]FeilongGuestDataSourceAdapter `tfsdk:"adapters"`
+}
+
+// FeilongGuestDataSourceAdapter describes one network adapter of the guest.
+type FeilongGuestDataSourceAdapter struct {
+	AdapterAddress	types.String	`tfsdk:"adapter_address"`
+	AdapterStatus	types.String	`tfsdk:"adapter_status"`
+	LANOwner	types.String	`tfsdk:"lan_owner"`
+	LANName		types.String	`tfsdk:"lan_name"`
+	MACAddress	types.String	`tfsdk:"mac_address"`
+	IPAddress	types.String	`tfsdk:"ip_address"`
+	IPVersion	types.String	`tfsdk:"ip_version"`
+}
+
+func (guest *FeilongGuestDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_guest"
+}
+
+func (guest *FeilongGuestDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong guest VM data source",
+
+		Attributes: map[string]schema.Attribute {
+			"userid": schema.StringAttribute {
+				MarkdownDescription:	"System name for z/VM",
+				Required:		true,
+			},
+			"vcpus": schema.Int64Attribute {
+				MarkdownDescription:	"Virtual CPUs count",
+				Computed:		true,
+			},
+			"online_vcpus": schema.Int64Attribute {
+				MarkdownDescription:	"Online virtual CPUs count, if the guest is running",
+				Computed:		true,
+			},
+			"cpu_time": schema.Int64Attribute {
+				MarkdownDescription:	"Used CPU time in microseconds",
+				Computed:		true,
+			},
+			"max_memory": schema.StringAttribute {
+				MarkdownDescription:	"Maximum memory size with unit",
+				Computed:		true,
+			},
+			"memory": schema.StringAttribute {
+				MarkdownDescription:	"Current memory size with unit",
+				Computed:		true,
+			},
+			"power_state": schema.StringAttribute {
+				MarkdownDescription:	"Power state (on or off)",
+				Computed:		true,
+			},
+			"os_distro": schema.StringAttribute {
+				MarkdownDescription:	"Operating system distribution, if the guest is running",
+				Computed:		true,
+			},
+			"kernel_info": schema.StringAttribute {
+				MarkdownDescription:	"Kernel information, if the guest is running",
+				Computed:		true,
+			},
+			"adapters": schema.ListNestedAttribute {
+				MarkdownDescription:	"Network adapters of the guest",
+				Computed:		true,
+				NestedObject:		schema.NestedAttributeObject {
+					Attributes: map[string]schema.Attribute {
+						"adapter_address": schema.StringAttribute {
+							MarkdownDescription:	"Virtual device of the interface",
+							Computed:		true,
+						},
+						"adapter_status": schema.StringAttribute {
+							MarkdownDescription:	"Status of the interface",
+							Computed:		true,
+						},
+						"lan_owner": schema.StringAttribute {
+							MarkdownDescription:	"Owner of the LAN the interface is connected to",
+							Computed:		true,
+						},
+						"lan_name": schema.StringAttribute {
+							MarkdownDescription:	"Name of the LAN or virtual switch the interface is connected to",
+							Computed:		true,
+						},
+						"mac_address": schema.StringAttribute {
+							MarkdownDescription:	"MAC address of the interface",
+							Computed:		true,
+						},
+						"ip_address": schema.StringAttribute {
+							MarkdownDescription:	"IP address of the interface",
+							Computed:		true,
+						},
+						"ip_version": schema.StringAttribute {
+							MarkdownDescription:	"IP version of the address (4 or 6)",
+							Computed:		true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (guest *FeilongGuestDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	guest.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (guest *FeilongGuestDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeilongGuestDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := guest.Client
+	userid := strings.ToUpper(data.UserId.ValueString())
+
+	// Obtain general info about the guest
+	var powerState string
+	guestInfo, err := client.GetGuestInfo(userid)
+	if err == nil {
+		data.VCPUs = types.Int64Value(int64(guestInfo.Output.NumCPUs))
+		data.CPUTime = types.Int64Value(int64(guestInfo.Output.CPUTimeMuSec))
+		data.MaxMemory = types.StringValue(fmt.Sprintf("%dM", guestInfo.Output.MaxMemKB / 1_024))
+		data.Memory = types.StringValue(fmt.Sprintf("%dM", guestInfo.Output.MemKB / 1_024))
+		powerState = guestInfo.Output.PowerState
+	} else {
+		// e.g. guests not managed by Feilong, only ask the hypervisor for their power state
+		tflog.Warn(ctx, "Cannot get info of guest " + userid + ": " + err.Error())
+		data.VCPUs = types.Int64Null()
+		data.CPUTime = types.Int64Null()
+		data.MaxMemory = types.StringNull()
+		data.Memory = types.StringNull()
+		powerStateResult, err := client.GetGuestPowerStateFromHypervisor(userid)
+		if err != nil {
+			resp.Diagnostics.AddError("Guest Querying Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+		powerState = powerStateResult.Output
+	}
+	data.PowerState = types.StringValue(powerState)
+
+	// Obtain info about the operating system, only available while running
+	data.OnlineVCPUs = types.Int64Null()
+	data.OSDistro = types.StringNull()
+	data.KernelInfo = types.StringNull()
+	if powerState == "on" {
+		// the OS might not be up yet, or the guest might have no agent
+		osInfo, err := client.GetGuestOSInfo(userid)
+		if err != nil {
+			tflog.Warn(ctx, "Cannot get OS info of guest " + userid + ": " + err.Error())
+		} else {
+			data.OSDistro = types.StringValue(osInfo.Output.OSDistro)
+			data.KernelInfo = types.StringValue(osInfo.Output.KernelInfo)
+		}
+
+		onlineCPUs, err := client.GetGuestOnlineCPUNum(userid)
+		if err != nil {
+			tflog.Warn(ctx, "Cannot get online CPUs of guest " + userid + ": " + err.Error())
+		} else {
+			data.OnlineVCPUs = types.Int64Value(int64(onlineCPUs.Output))
+		}
+	}
+
+	// Obtain info about the network adapters
+	data.Adapters = []FeilongGuestDataSourceAdapter {}
+	adaptersInfo, err := client.GetGuestAdaptersInfo(userid)
+	if err != nil {
+		// e.g. guests not managed by Feilong, leave their adapters empty
+		tflog.Warn(ctx, "Cannot get adapters of guest " + userid + ": " + err.Error())
+	} else {
+		for _, adapter := range adaptersInfo.Output.Adapters {
+			data.Adapters = append(data.Adapters, FeilongGuestDataSourceAdapter {
+				AdapterAddress:	types.StringValue(adapter.AdapterAddress),
+				AdapterStatus:	types.StringValue(adapter.AdapterStatus),
+				LANOwner:	types.StringValue(adapter.LANOwner),
+				LANName:	types.StringValue(adapter.LANName),
+				MACAddress:	types.StringValue(adapter.MACAddress),
+				IPAddress:	types.StringValue(adapter.IPAddress),
+				IPVersion:	types.StringValue(adapter.IPVersion),
+			})
+		}
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read a Feilong guest data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
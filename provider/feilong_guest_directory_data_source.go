@@ -0,0 +1,414 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FeilongGuestDirectoryDataSource{}
+
+func NewFeilongGuestDirectoryDataSource() datasource.DataSource {
+	return &FeilongGuestDirectoryDataSource{}
+}
+
+// FeilongGuestDirectoryDataSource defines the data source implementation.
+type FeilongGuestDirectoryDataSource struct {
+	Client *feilong.Client
+}
+
+// FeilongGuestDirectoryDataSourceModel describes the data source data model.
+type FeilongGuestDirectoryDataSourceModel struct {
+	UserId		types.String	`tfsdk:"userid"`
+	Statements	[]types.String	`tfsdk:"statements"`
+	Storage		types.String	`tfsdk:"storage"`
+	MaxStorage	types.String	`tfsdk:"max_storage"`
+	Privileges	types.String	`tfsdk:"privileges"`
+	Machine		types.String	`tfsdk:"machine"`
+	MaxCPUs		types.String	`tfsdk:"max_cpus"`
+	Include		types.String	`tfsdk:"include"`
+	IPL		types.String	`tfsdk:"ipl"`
+	Account		types.String	`tfsdk:"account"`
+	Comments	[]types.String	`tfsdk:"comments"`
+	CPUs		[]FeilongGuestDirectoryDataSourceCPU `tfsdk:"cpus"`
+	Minidisks	[]FeilongGuestDirectoryDataSourceMinidisk `tfsdk:"minidisks"`
+	NICs		[]FeilongGuestDirectoryDataSourceNIC `tfsdk:"nics"`
+}
+
+// FeilongGuestDirectoryDataSourceCPU describes one CPU statement.
+type FeilongGuestDirectoryDataSourceCPU struct {
+	Address		types.String	`tfsdk:"address"`
+	Options		types.String	`tfsdk:"options"`
+}
+
+// FeilongGuestDirectoryDataSourceMinidisk describes one MDISK statement.
+type FeilongGuestDirectoryDataSourceMinidisk struct {
+	VDev		types.String	`tfsdk:"vdev"`
+	Type		types.String	`tfsdk:"type"`
+	Start		types.String	`tfsdk:"start"`
+	Size		types.String	`tfsdk:"size"`
+	RealDevice	types.String	`tfsdk:"real_device"`
+	Volume		types.String	`tfsdk:"volume"`
+	Mode		types.String	`tfsdk:"mode"`
+}
+
+// FeilongGuestDirectoryDataSourceNIC describes the NICDEF statements of one adapter.
+type FeilongGuestDirectoryDataSourceNIC struct {
+	VDev		types.String	`tfsdk:"vdev"`
+	Type		types.String	`tfsdk:"type"`
+	LANOwner	types.String	`tfsdk:"lan_owner"`
+	VSwitch		types.String	`tfsdk:"vswitch"`
+	MACId		types.String	`tfsdk:"mac_id"`
+}
+
+func (directory *FeilongGuestDirectoryDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_guest_directory"
+}
+
+func (directory *FeilongGuestDirectoryDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong guest directory entry data source",
+
+		Attributes: map[string]schema.Attribute {
+			"userid": schema.StringAttribute {
+				MarkdownDescription:	"System name for z/VM",
+				Required:		true,
+			},
+			"statements": schema.ListAttribute {
+				MarkdownDescription:	"Raw directory statements, without the password",
+				ElementType:		types.StringType,
+				Computed:		true,
+			},
+			"storage": schema.StringAttribute {
+				MarkdownDescription:	"Initial storage size from the USER statement",
+				Computed:		true,
+			},
+			"max_storage": schema.StringAttribute {
+				MarkdownDescription:	"Maximum storage size from the USER statement",
+				Computed:		true,
+			},
+			"privileges": schema.StringAttribute {
+				MarkdownDescription:	"Privilege classes from the USER statement",
+				Computed:		true,
+			},
+			"machine": schema.StringAttribute {
+				MarkdownDescription:	"Virtual machine mode from the MACHINE statement",
+				Computed:		true,
+			},
+			"max_cpus": schema.StringAttribute {
+				MarkdownDescription:	"Maximum CPUs count from the MACHINE statement",
+				Computed:		true,
+			},
+			"include": schema.StringAttribute {
+				MarkdownDescription:	"Profile from the INCLUDE statement",
+				Computed:		true,
+			},
+			"ipl": schema.StringAttribute {
+				MarkdownDescription:	"Operands of the IPL statement",
+				Computed:		true,
+			},
+			"account": schema.StringAttribute {
+				MarkdownDescription:	"Operands of the ACCOUNT statement",
+				Computed:		true,
+			},
+			"comments": schema.ListAttribute {
+				MarkdownDescription:	"Texts of the COMMENT statements",
+				ElementType:		types.StringType,
+				Computed:		true,
+			},
+			"cpus": schema.ListNestedAttribute {
+				MarkdownDescription:	"CPU statements",
+				Computed:		true,
+				NestedObject:		schema.NestedAttributeObject {
+					Attributes: map[string]schema.Attribute {
+						"address": schema.StringAttribute {
+							MarkdownDescription:	"Virtual CPU address",
+							Computed:		true,
+						},
+						"options": schema.StringAttribute {
+							MarkdownDescription:	"Other operands, e.g. BASE",
+							Computed:		true,
+						},
+					},
+				},
+			},
+			"minidisks": schema.ListNestedAttribute {
+				MarkdownDescription:	"MDISK statements",
+				Computed:		true,
+				NestedObject:		schema.NestedAttributeObject {
+					Attributes: map[string]schema.Attribute {
+						"vdev": schema.StringAttribute {
+							MarkdownDescription:	"Virtual device of the minidisk",
+							Computed:		true,
+						},
+						"type": schema.StringAttribute {
+							MarkdownDescription:	"Device type, e.g. 3390 or FB-512",
+							Computed:		true,
+						},
+						"start": schema.StringAttribute {
+							MarkdownDescription:	"First cylinder or block, or V-DISK, T-DISK...",
+							Computed:		true,
+						},
+						"size": schema.StringAttribute {
+							MarkdownDescription:	"Size in cylinders or blocks",
+							Computed:		true,
+						},
+						"real_device": schema.StringAttribute {
+							MarkdownDescription:	"Real device number of a DEVNO minidisk",
+							Computed:		true,
+						},
+						"volume": schema.StringAttribute {
+							MarkdownDescription:	"Volume label",
+							Computed:		true,
+						},
+						"mode": schema.StringAttribute {
+							MarkdownDescription:	"Access mode, e.g. MR",
+							Computed:		true,
+						},
+					},
+				},
+			},
+			"nics": schema.ListNestedAttribute {
+				MarkdownDescription:	"Network adapters from the NICDEF statements",
+				Computed:		true,
+				NestedObject:		schema.NestedAttributeObject {
+					Attributes: map[string]schema.Attribute {
+						"vdev": schema.StringAttribute {
+							MarkdownDescription:	"Virtual device of the adapter",
+							Computed:		true,
+						},
+						"type": schema.StringAttribute {
+							MarkdownDescription:	"Adapter type, e.g. QDIO",
+							Computed:		true,
+						},
+						"lan_owner": schema.StringAttribute {
+							MarkdownDescription:	"Owner of the LAN, e.g. SYSTEM",
+							Computed:		true,
+						},
+						"vswitch": schema.StringAttribute {
+							MarkdownDescription:	"Name of the LAN or virtual switch",
+							Computed:		true,
+						},
+						"mac_id": schema.StringAttribute {
+							MarkdownDescription:	"Last 3 bytes of the MAC address",
+							Computed:		true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (directory *FeilongGuestDirectoryDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	directory.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (directory *FeilongGuestDirectoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeilongGuestDirectoryDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Obtain the directory entry
+	client := directory.Client
+	userid := strings.ToUpper(data.UserId.ValueString())
+	var statements []string
+	result, err := client.GetGuestUserDirectory(userid)
+	if err == nil {
+		statements = result.Output.UserDirect
+	} else {
+		if !endpointNotSupported(err) {
+			resp.Diagnostics.AddError("Directory Querying Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+		tflog.Info(ctx, "Getting user directory failed, falling back to guest definition: " + err.Error())
+		definition, err := client.ShowGuestDefinition(userid)
+		if err != nil {
+			resp.Diagnostics.AddError("Directory Querying Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+		statements = definition.Output.UserDirect
+	}
+
+	// Parse it
+	parseDirectory(&data, statements)
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read a Feilong guest directory data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// For internal use
+
+func parseDirectory(data *FeilongGuestDirectoryDataSourceModel, statements []string) {
+	data.Statements = []types.String {}
+	data.Storage = types.StringNull()
+	data.MaxStorage = types.StringNull()
+	data.Privileges = types.StringNull()
+	data.Machine = types.StringNull()
+	data.MaxCPUs = types.StringNull()
+	data.Include = types.StringNull()
+	data.IPL = types.StringNull()
+	data.Account = types.StringNull()
+	data.Comments = []types.String {}
+	data.CPUs = []FeilongGuestDirectoryDataSourceCPU {}
+	data.Minidisks = []FeilongGuestDirectoryDataSourceMinidisk {}
+	data.NICs = []FeilongGuestDirectoryDataSourceNIC {}
+
+	// A NIC may be defined over several NICDEF statements
+	nicIndex := map[string]int {}
+
+	for _, statement := range statements {
+		fields := strings.Fields(statement)
+		if len(fields) == 0 {
+			continue
+		}
+		keyword := strings.ToUpper(fields[0])
+		rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(statement), fields[0]))
+
+		switch keyword {
+		case "USER", "IDENTITY":
+			// USER userid password storage max_storage privileges
+			if len(fields) > 2 {
+				// never expose the password
+				statement = maskDirectoryFields(fields, 2, 3)
+			}
+			data.Storage = directoryField(fields, 3)
+			data.MaxStorage = directoryField(fields, 4)
+			data.Privileges = directoryField(fields, 5)
+		case "MACHINE":
+			// MACHINE mode max_cpus
+			data.Machine = directoryField(fields, 1)
+			data.MaxCPUs = directoryField(fields, 2)
+		case "INCLUDE":
+			data.Include = directoryField(fields, 1)
+		case "IPL":
+			data.IPL = types.StringValue(rest)
+		case "ACCOUNT":
+			data.Account = types.StringValue(rest)
+		case "COMMENT":
+			data.Comments = append(data.Comments, types.StringValue(rest))
+		case "CPU":
+			// CPU address options...
+			if len(fields) > 1 {
+				data.CPUs = append(data.CPUs, FeilongGuestDirectoryDataSourceCPU {
+					Address:	types.StringValue(fields[1]),
+					Options:	types.StringValue(strings.Join(fields[2:], " ")),
+				})
+			}
+		case "MDISK":
+			// MDISK vdev type start size volume mode passwords...
+			// MDISK vdev type V-DISK|T-DISK size mode passwords...
+			// MDISK vdev type DEVNO real_device mode passwords...
+			if len(fields) > 1 {
+				modeIndex := 6
+				size := directoryField(fields, 4)
+				realDevice := types.StringNull()
+				volume := directoryField(fields, 5)
+				switch strings.ToUpper(directoryField(fields, 3).ValueString()) {
+				case "V-DISK", "T-DISK":
+					modeIndex = 5
+					volume = types.StringNull()
+				case "DEVNO":
+					modeIndex = 5
+					realDevice = size
+					size = types.StringNull()
+					volume = types.StringNull()
+				}
+				data.Minidisks = append(data.Minidisks, FeilongGuestDirectoryDataSourceMinidisk {
+					VDev:		types.StringValue(fields[1]),
+					Type:		directoryField(fields, 2),
+					Start:		directoryField(fields, 3),
+					Size:		size,
+					RealDevice:	realDevice,
+					Volume:		volume,
+					Mode:		directoryField(fields, modeIndex),
+				})
+				// never expose the read, write and multi-write passwords
+				statement = maskDirectoryFields(fields, modeIndex + 1, len(fields))
+			}
+		case "LINK":
+			// LINK userid vdev1 vdev2 mode passwords...
+			statement = maskDirectoryFields(fields, 5, len(fields))
+		case "NICDEF":
+			// NICDEF vdev TYPE type LAN owner name MACID macid...
+			if len(fields) > 1 {
+				vdev := fields[1]
+				i, found := nicIndex[vdev]
+				if !found {
+					i = len(data.NICs)
+					nicIndex[vdev] = i
+					data.NICs = append(data.NICs, FeilongGuestDirectoryDataSourceNIC {
+						VDev:		types.StringValue(vdev),
+						Type:		types.StringNull(),
+						LANOwner:	types.StringNull(),
+						VSwitch:	types.StringNull(),
+						MACId:		types.StringNull(),
+					})
+				}
+				nic := &data.NICs[i]
+				for j := 2; j < len(fields); j++ {
+					switch strings.ToUpper(fields[j]) {
+					case "TYPE":
+						nic.Type = directoryField(fields, j + 1)
+						j++
+					case "LAN":
+						nic.LANOwner = directoryField(fields, j + 1)
+						nic.VSwitch = directoryField(fields, j + 2)
+						j += 2
+					case "MACID":
+						nic.MACId = directoryField(fields, j + 1)
+						j++
+					}
+				}
+			}
+		}
+		data.Statements = append(data.Statements, types.StringValue(statement))
+	}
+}
+
+func directoryField(fields []string, i int) types.String {
+	if i >= len(fields) {
+		return types.StringNull()
+	}
+	return types.StringValue(fields[i])
+}
+
+// Mask fields from first (included) to last (excluded)
+func maskDirectoryFields(fields []string, first int, last int) string {
+	masked := append([]string {}, fields...)
+	for i := first; i < last && i < len(masked); i++ {
+		masked[i] = "XXXXXXXX"
+	}
+	return strings.Join(masked, " ")
+}
+
+func endpointNotSupported(err error) bool {
+	// older z/VM connectors do not know the endpoint at all
+	return strings.HasPrefix(err.Error(), "HTTP status: 404,") || strings.HasPrefix(err.Error(), "HTTP status: 405,")
+}
@@ -0,0 +1,155 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseDirectory(t *testing.T) {
+	var data FeilongGuestDirectoryDataSourceModel
+
+	parseDirectory(&data, []string {
+		"USER LINUX097 SECRET 2G 4G G",
+		"INCLUDE IBMDFLT",
+		"COMMENT Database server",
+		"ACCOUNT 1 LINUX",
+		"MACHINE ESA 4",
+		"CPU 00 BASE",
+		"CPU 01",
+		"IPL 0100",
+		"NICDEF 1000 TYPE QDIO LAN SYSTEM DEVNET",
+		"NICDEF 1000 MACID 123456",
+		"MDISK 0100 3390 1 10016 VOL001 MR RPASS WPASS MPASS",
+		"MDISK 0101 FB-512 V-DISK 128000 MR RPASS",
+		"MDISK 0102 3390 DEVNO 1A2B MW RPASS WPASS",
+		"LINK MAINT 0190 0190 RR LPASS",
+	})
+
+	stringValues := func(values ...string) []types.String {
+		result := []types.String {}
+		for _, value := range values {
+			result = append(result, types.StringValue(value))
+		}
+		return result
+	}
+
+	if !reflect.DeepEqual(data.Statements, stringValues(
+		"USER LINUX097 XXXXXXXX 2G 4G G",
+		"INCLUDE IBMDFLT",
+		"COMMENT Database server",
+		"ACCOUNT 1 LINUX",
+		"MACHINE ESA 4",
+		"CPU 00 BASE",
+		"CPU 01",
+		"IPL 0100",
+		"NICDEF 1000 TYPE QDIO LAN SYSTEM DEVNET",
+		"NICDEF 1000 MACID 123456",
+		"MDISK 0100 3390 1 10016 VOL001 MR XXXXXXXX XXXXXXXX XXXXXXXX",
+		"MDISK 0101 FB-512 V-DISK 128000 MR XXXXXXXX",
+		"MDISK 0102 3390 DEVNO 1A2B MW XXXXXXXX XXXXXXXX",
+		"LINK MAINT 0190 0190 RR XXXXXXXX",
+	)) {
+		t.Errorf("unexpected statements: %v", data.Statements)
+	}
+
+	if data.Storage.ValueString() != "2G" || data.MaxStorage.ValueString() != "4G" || data.Privileges.ValueString() != "G" {
+		t.Errorf("unexpected USER values: %v %v %v", data.Storage, data.MaxStorage, data.Privileges)
+	}
+	if data.Machine.ValueString() != "ESA" || data.MaxCPUs.ValueString() != "4" {
+		t.Errorf("unexpected MACHINE values: %v %v", data.Machine, data.MaxCPUs)
+	}
+	if data.Include.ValueString() != "IBMDFLT" || data.IPL.ValueString() != "0100" || data.Account.ValueString() != "1 LINUX" {
+		t.Errorf("unexpected INCLUDE, IPL or ACCOUNT values: %v %v %v", data.Include, data.IPL, data.Account)
+	}
+	if !reflect.DeepEqual(data.Comments, stringValues("Database server")) {
+		t.Errorf("unexpected comments: %v", data.Comments)
+	}
+
+	expectedCPUs := []FeilongGuestDirectoryDataSourceCPU {
+		{ Address: types.StringValue("00"), Options: types.StringValue("BASE") },
+		{ Address: types.StringValue("01"), Options: types.StringValue("") },
+	}
+	if !reflect.DeepEqual(data.CPUs, expectedCPUs) {
+		t.Errorf("unexpected CPUs: %v", data.CPUs)
+	}
+
+	expectedMinidisks := []FeilongGuestDirectoryDataSourceMinidisk {
+		{
+			VDev:		types.StringValue("0100"),
+			Type:		types.StringValue("3390"),
+			Start:		types.StringValue("1"),
+			Size:		types.StringValue("10016"),
+			RealDevice:	types.StringNull(),
+			Volume:		types.StringValue("VOL001"),
+			Mode:		types.StringValue("MR"),
+		},
+		{
+			VDev:		types.StringValue("0101"),
+			Type:		types.StringValue("FB-512"),
+			Start:		types.StringValue("V-DISK"),
+			Size:		types.StringValue("128000"),
+			RealDevice:	types.StringNull(),
+			Volume:		types.StringNull(),
+			Mode:		types.StringValue("MR"),
+		},
+		{
+			VDev:		types.StringValue("0102"),
+			Type:		types.StringValue("3390"),
+			Start:		types.StringValue("DEVNO"),
+			Size:		types.StringNull(),
+			RealDevice:	types.StringValue("1A2B"),
+			Volume:		types.StringNull(),
+			Mode:		types.StringValue("MW"),
+		},
+	}
+	if !reflect.DeepEqual(data.Minidisks, expectedMinidisks) {
+		t.Errorf("unexpected minidisks: %v", data.Minidisks)
+	}
+
+	expectedNICs := []FeilongGuestDirectoryDataSourceNIC {
+		{
+			VDev:		types.StringValue("1000"),
+			Type:		types.StringValue("QDIO"),
+			LANOwner:	types.StringValue("SYSTEM"),
+			VSwitch:	types.StringValue("DEVNET"),
+			MACId:		types.StringValue("123456"),
+		},
+	}
+	if !reflect.DeepEqual(data.NICs, expectedNICs) {
+		t.Errorf("unexpected NICs: %v", data.NICs)
+	}
+}
+
+func TestParseDirectoryEmpty(t *testing.T) {
+	var data FeilongGuestDirectoryDataSourceModel
+
+	parseDirectory(&data, []string {})
+
+	if !data.Storage.IsNull() || !data.Machine.IsNull() || !data.IPL.IsNull() || !data.Account.IsNull() {
+		t.Errorf("values should be null for an empty directory entry")
+	}
+	if len(data.Statements) != 0 || len(data.CPUs) != 0 || len(data.Minidisks) != 0 || len(data.NICs) != 0 {
+		t.Errorf("lists should be empty for an empty directory entry")
+	}
+}
+
+func TestEndpointNotSupported(t *testing.T) {
+	if !endpointNotSupported(errors.New("HTTP status: 404, body: The resource could not be found.")) {
+		t.Errorf("a 404 status should mean that the endpoint is not supported")
+	}
+	if endpointNotSupported(errors.New("HTTP status: 401, body: Unauthorized")) {
+		t.Errorf("an authentication error should not mean that the endpoint is not supported")
+	}
+	if endpointNotSupported(errors.New("dial tcp 10.0.0.1:8080: connect: connection refused")) {
+		t.Errorf("a transport error should not mean that the endpoint is not supported")
+	}
+}
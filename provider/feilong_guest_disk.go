@@ -0,0 +1,321 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &FeilongGuestDisk{}
+
+func NewFeilongGuestDisk() resource.Resource {
+	return &FeilongGuestDisk{}
+}
+
+// FeilongGuestDisk defines the resource implementation.
+type FeilongGuestDisk struct {
+	Client *feilong.Client
+}
+
+// FeilongGuestDiskModel describes the resource data model.
+type FeilongGuestDiskModel struct {
+	Name		types.String	`tfsdk:"name"`
+	UserId		types.String	`tfsdk:"userid"`
+	VDev		types.String	`tfsdk:"vdev"`
+	Size		types.String	`tfsdk:"size"`
+	DiskPool	types.String	`tfsdk:"disk_pool"`
+	Format		types.String	`tfsdk:"format"`
+	MountPoint	types.String	`tfsdk:"mount_point"`
+}
+
+func (disk *FeilongGuestDisk) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_guest_disk"
+}
+
+func (disk *FeilongGuestDisk) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong guest additional minidisk resource",
+
+		Attributes: map[string]schema.Attribute {
+			"name": schema.StringAttribute {
+				MarkdownDescription:	"Arbitrary name for the resource",
+				Required:		true,
+			},
+			"userid": schema.StringAttribute {
+				MarkdownDescription:	"System name for z/VM of the guest owning the disk",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vdev": schema.StringAttribute {
+				MarkdownDescription:	"Virtual device number of the disk",
+				Optional:		true,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"size": schema.StringAttribute {
+				MarkdownDescription:	"Disk size with unit (T, G, M, K, B)",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"disk_pool": schema.StringAttribute {
+				MarkdownDescription:	"Disk pool where to allocate the disk, e.g. ECKD:POOL1",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"format": schema.StringAttribute {
+				MarkdownDescription:	"File system to format the disk with (ext2, ext3, ext4, xfs, or swap)",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"mount_point": schema.StringAttribute {
+				MarkdownDescription:	"Directory where to mount the disk in the guest",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (disk *FeilongGuestDisk) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	disk.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (disk *FeilongGuestDisk) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FeilongGuestDiskModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Compute values passed to Feilong API but not part of the data model
+	userid := data.UserId.ValueString()
+	vdev := data.VDev.ValueString()
+	format := data.Format.ValueString()
+	mountPoint := data.MountPoint.ValueString()
+	if mountPoint != "" && format == "" {
+		resp.Diagnostics.AddError("Missing Value", "A disk must be formatted to be mounted")
+		return
+	}
+
+	// Create the minidisk, formatting it right away unless it is formatted in the guest below
+	client := disk.Client
+	addFormat := format
+	if mountPoint != "" {
+		addFormat = ""
+	}
+	addParams := feilong.AddGuestDisksParams {
+		DiskList:	[]feilong.GuestDisk {
+			{
+				Size:		data.Size.ValueString(),
+				Format:		addFormat,
+				VDev:		vdev,
+				DiskPool:	data.DiskPool.ValueString(),
+			},
+		},
+	}
+	result, err := client.AddGuestDisks(userid, &addParams)
+	if err != nil {
+		resp.Diagnostics.AddError("Creation Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Compute computed fields
+	if vdev == "" {
+		if len(result.Output) != 1 {
+			resp.Diagnostics.AddError("Minidisk Not Found Error", fmt.Sprintf("Got number: %d, the minidisk might need to be deleted by hand", len(result.Output)))
+			return
+		}
+		vdev = result.Output[0].VDev
+		data.VDev = types.StringValue(vdev)
+	}
+
+	// Format and mount the minidisk in the guest
+	if mountPoint != "" {
+		configureParams := feilong.ConfigureGuestDisksParams {
+			DiskList:	[]feilong.ConfigureGuestDisk {
+				{
+					VDev:		vdev,
+					Format:		format,
+					MountDirectory:	mountPoint,
+				},
+			},
+		}
+		err = client.ConfigureGuestDisks(userid, &configureParams)
+		if err != nil {
+			resp.Diagnostics.AddError("Minidisk Configuration Error", fmt.Sprintf("Got error: %s", err))
+			// do not leave an untracked minidisk behind
+			deleteParams := feilong.DeleteGuestDisksParams {
+				VDevList:	[]string { vdev },
+			}
+			err = client.DeleteGuestDisks(userid, &deleteParams)
+			if err != nil {
+				resp.Diagnostics.AddError("Minidisk Cleanup Error", fmt.Sprintf("Got error: %s", err))
+			}
+			return
+		}
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Created a Feilong guest disk resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (disk *FeilongGuestDisk) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FeilongGuestDiskModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := disk.Client
+	userid := data.UserId.ValueString()
+	vdev := data.VDev.ValueString()
+
+	// Obtain minidisk info
+	minidisksInfo, err := client.GetGuestMinidisksInfo(userid)
+	if err != nil {
+		resp.Diagnostics.AddError("Minidisks Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	var minidisk *feilong.GetGuestMinidisksInfoMinidisk
+	for i, m := range minidisksInfo.Output.Minidisks {
+		if strings.EqualFold(m.VDev, vdev) {
+			minidisk = &minidisksInfo.Output.Minidisks[i]
+			break
+		}
+	}
+	if minidisk == nil {
+		// the minidisk was deleted outside of terraform
+		tflog.Info(ctx, "Minidisk " + vdev + " of guest " + userid + " not found, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Read disk size
+	declaredSize, err := convertToMegabytes(data.Size.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Conversion Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	obtainedSize, err := minidiskSizeInMegabytes(minidisk)
+	if err != nil {
+		resp.Diagnostics.AddError("Unknown Minidisk Unit Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	if declaredSize == obtainedSize {
+		// do not overwrite disk size if value equal but a different unit
+		tflog.Info(ctx, "Not replacing disk size " + data.Size.ValueString() + " with equal value " + strconv.Itoa(obtainedSize) + "M")
+	} else {
+		data.Size = types.StringValue(strconv.Itoa(obtainedSize) + "M")
+	}
+
+	// CAVEATS:
+	//  - the disk pool cannot be determined after the creation
+	//  - the file system and the mount point cannot be determined after the creation
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read characteristics of Feilong guest disk resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (disk *FeilongGuestDisk) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data FeilongGuestDiskModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// All attributes of the disk itself require a replacement,
+	// so only the resource name can change here
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Updated Feilong guest disk resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (disk *FeilongGuestDisk) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FeilongGuestDiskModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := disk.Client
+	userid := data.UserId.ValueString()
+
+	// Delete the minidisk
+	deleteParams := feilong.DeleteGuestDisksParams {
+		VDevList:	[]string { data.VDev.ValueString() },
+	}
+	err := client.DeleteGuestDisks(userid, &deleteParams)
+	if err != nil {
+		resp.Diagnostics.AddError("Deletion Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Deleted the Feilong guest disk resource")
+}
+
+// For internal use
+
+func minidiskSizeInMegabytes(minidisk *feilong.GetGuestMinidisksInfoMinidisk) (int, error) {
+	switch minidisk.DeviceUnits {
+		case "Cylinders":
+			// tracks/cylinder=15  blocks/track=12  kilobytes/block=4  15*12*4=720
+			return (minidisk.DeviceSize * 720) / 1_024, nil
+		case "Blocks":
+			// bytes/block=512
+			return minidisk.DeviceSize / 2_048, nil
+	}
+	return 0, fmt.Errorf("Unknown minidisk unit %s", minidisk.DeviceUnits)
+}
@@ -0,0 +1,435 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &FeilongGuestNIC{}
+
+func NewFeilongGuestNIC() resource.Resource {
+	return &FeilongGuestNIC{}
+}
+
+// FeilongGuestNIC defines the resource implementation.
+type FeilongGuestNIC struct {
+	Client *feilong.Client
+}
+
+// FeilongGuestNICModel describes the resource data model.
+type FeilongGuestNICModel struct {
+	Name		types.String	`tfsdk:"name"`
+	UserId		types.String	`tfsdk:"userid"`
+	AdapterAddress	types.String	`tfsdk:"adapter_address"`
+	MAC		types.String	`tfsdk:"mac"`
+	VSwitch		types.String	`tfsdk:"vswitch"`
+	OSVersion	types.String	`tfsdk:"os_version"`
+	Method		types.String	`tfsdk:"method"`
+	IP		types.String	`tfsdk:"ip"`
+	DNSServers	types.List	`tfsdk:"dns_servers"`
+	Gateway		types.String	`tfsdk:"gateway"`
+	Network		types.String	`tfsdk:"network"`
+	MACAddress	types.String	`tfsdk:"mac_address"`
+	IPAddress	types.String	`tfsdk:"ip_address"`
+}
+
+func (nic *FeilongGuestNIC) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_guest_nic"
+}
+
+func (nic *FeilongGuestNIC) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong guest additional network interface resource",
+
+		Attributes: map[string]schema.Attribute {
+			"name": schema.StringAttribute {
+				MarkdownDescription:	"Arbitrary name for the resource",
+				Required:		true,
+			},
+			"userid": schema.StringAttribute {
+				MarkdownDescription:	"System name for z/VM of the guest owning the interface",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"adapter_address": schema.StringAttribute {
+				MarkdownDescription:	"Virtual device of the interface",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"mac": schema.StringAttribute {
+				MarkdownDescription:	"Desired MAC address of the interface",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vswitch": schema.StringAttribute {
+				MarkdownDescription:	"Name of virtual switch to connect to",
+				Optional:		true,
+				Computed:		true,
+				Default:		stringdefault.StaticString("DEVNET"),
+			},
+			"os_version": schema.StringAttribute {
+				MarkdownDescription:	"Operating system version, e.g. sles15.7; if given, the interface is also configured in the guest",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"method": schema.StringAttribute {
+				MarkdownDescription:	"Network method used to configure the interface",
+				Optional:		true,
+				Computed:		true,
+				Default:		stringdefault.StaticString("dhcp"),
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ip": schema.StringAttribute {
+				MarkdownDescription:	"Desired IPv4 address of the interface",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"dns_servers": schema.ListAttribute {
+				MarkdownDescription:	"List of DNS servers associated to the interface",
+				ElementType:		types.StringType,
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.List {
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"gateway": schema.StringAttribute {
+				MarkdownDescription:	"IPv4 address of gateway for the interface",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"network": schema.StringAttribute {
+				MarkdownDescription:	"Network of the interface in CIDR notation",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"mac_address": schema.StringAttribute {
+				MarkdownDescription:	"MAC address of the interface after creation",
+				Computed:		true,
+			},
+			"ip_address": schema.StringAttribute {
+				MarkdownDescription:	"IP address of the interface after creation",
+				Computed:		true,
+			},
+		},
+	}
+}
+
+func (nic *FeilongGuestNIC) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	nic.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (nic *FeilongGuestNIC) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FeilongGuestNICModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Compute values passed to Feilong API but not part of the data model
+	userid := data.UserId.ValueString()
+	adapterAddress := data.AdapterAddress.ValueString()
+	mac := data.MAC.ValueString()
+	vswitch := data.VSwitch.ValueString()
+	osVersion := data.OSVersion.ValueString()
+	dnsServers := []string {}
+	resp.Diagnostics.Append(data.DNSServers.ElementsAs(ctx, &dnsServers, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := nic.Client
+	active := true; couple := true
+	if osVersion == "" {
+		// Create the network interface
+		createNICParams := feilong.CreateGuestNICParams {
+			VDev:		adapterAddress,
+			MACAddress:	mac,
+			Active:		&active,
+		}
+		err := client.CreateGuestNIC(userid, &createNICParams)
+		if err != nil {
+			resp.Diagnostics.AddError("NIC Creation Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+	} else {
+		// Create the network interface and configure it in the guest
+		guestNetwork := feilong.GuestNetwork {
+			Method:		data.Method.ValueString(),
+			IPAddress:	data.IP.ValueString(),
+			DNSAddresses:	dnsServers,
+			GatewayAddress:	data.Gateway.ValueString(),
+			CIDR:		data.Network.ValueString(),
+			NICVDev:	adapterAddress,
+			MACAddress:	mac,
+			// NICId, OSADevice, and Hostname unconfigured
+		}
+		createGuestNetworkInterfaceParams := feilong.CreateGuestNetworkInterfaceParams {
+			OSVersion:	osVersion,
+			GuestNetworks:	[]feilong.GuestNetwork { guestNetwork },
+			Active:		&active,
+		}
+		err := client.CreateGuestNetworkInterface(userid, &createGuestNetworkInterfaceParams)
+		if err != nil {
+			resp.Diagnostics.AddError("Network Interface Configuration Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+	}
+
+	// Couple the network interface with the virtual switch
+	updateNICParams := feilong.UpdateGuestNICParams {
+		Couple:		&couple,
+		Active:		&active,
+		VSwitch:	vswitch,
+	}
+	err := client.UpdateGuestNIC(userid, adapterAddress, &updateNICParams)
+	if err != nil {
+		resp.Diagnostics.AddError("NIC Coupling Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Get computed values
+	adapter, err := findAdapter(client, userid, adapterAddress)
+	if err != nil {
+		resp.Diagnostics.AddError("Network Adapter Info Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	if adapter == nil {
+		resp.Diagnostics.AddError("Network Adapter Not Found Error", fmt.Sprintf("Adapter %s not listed after creation", adapterAddress))
+		return
+	}
+	data.MACAddress = types.StringValue(adapter.MACAddress)
+	data.IPAddress = types.StringValue(adapter.IPAddress)
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Created a Feilong guest NIC resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (nic *FeilongGuestNIC) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FeilongGuestNICModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := nic.Client
+	userid := data.UserId.ValueString()
+	adapterAddress := data.AdapterAddress.ValueString()
+
+	// Obtain network adapter info
+	adapter, err := findAdapter(client, userid, adapterAddress)
+	if err != nil {
+		resp.Diagnostics.AddError("Network Adapter Info Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	if adapter == nil {
+		// the network interface was deleted outside of terraform
+		tflog.Info(ctx, "Adapter " + adapterAddress + " of guest " + userid + " not found, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Read virtual switch name
+	if adapter.LANName == "" {
+		tflog.Info(ctx, "Not replacing virtual switch " + data.VSwitch.ValueString() + " of uncoupled adapter")
+	} else {
+		data.VSwitch = types.StringValue(adapter.LANName)
+	}
+
+	// Read MAC address
+	data.MACAddress = types.StringValue(adapter.MACAddress)
+
+	// Read IP address
+	declaredIPAddress := data.IPAddress.ValueString()
+	obtainedIPAddress := adapter.IPAddress
+	if adapter.IPVersion == "6" && strings.HasPrefix(obtainedIPAddress, "fe80:") {
+		// do not overwrite an IPv4 address with a link-local IPv6 address
+		tflog.Info(ctx, "Not replacing IP address " + declaredIPAddress + " with an IPv6 link-local address " + obtainedIPAddress)
+	} else {
+		data.IPAddress = types.StringValue(obtainedIPAddress)
+	}
+
+	// CAVEATS:
+	//  - the OS version, network method, IP address, DNS servers, gateway and network
+	//    used to configure the interface cannot be determined after the creation
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read characteristics of Feilong guest NIC resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (nic *FeilongGuestNIC) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data FeilongGuestNICModel
+	var state FeilongGuestNICModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Also read current state, for comparaison
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := nic.Client
+	userid := data.UserId.ValueString()
+	adapterAddress := data.AdapterAddress.ValueString()
+
+	// Address desired vswitch changes
+	oldVSwitch := state.VSwitch.ValueString()
+	newVSwitch := data.VSwitch.ValueString()
+	if newVSwitch != oldVSwitch {
+		active := true; couple := false
+		uncoupleParams := feilong.UpdateGuestNICParams {
+			Couple:		&couple,
+			Active:		&active,
+		}
+		err := client.UpdateGuestNIC(userid, adapterAddress, &uncoupleParams)
+		if err != nil {
+			resp.Diagnostics.AddError("NIC Uncoupling Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+
+		couple = true
+		coupleParams := feilong.UpdateGuestNICParams {
+			Couple:		&couple,
+			Active:		&active,
+			VSwitch:	newVSwitch,
+		}
+		err = client.UpdateGuestNIC(userid, adapterAddress, &coupleParams)
+		if err != nil {
+			resp.Diagnostics.AddError("NIC Coupling Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+		tflog.Info(ctx, "Moved adapter " + adapterAddress + " from virtual switch " + oldVSwitch + " to " + newVSwitch)
+	}
+
+	// Get computed values
+	adapter, err := findAdapter(client, userid, adapterAddress)
+	if err != nil {
+		resp.Diagnostics.AddError("Network Adapter Info Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	if adapter == nil {
+		resp.Diagnostics.AddError("Network Adapter Not Found Error", fmt.Sprintf("Adapter %s not listed after update", adapterAddress))
+		return
+	}
+	data.MACAddress = types.StringValue(adapter.MACAddress)
+	data.IPAddress = types.StringValue(adapter.IPAddress)
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Updated Feilong guest NIC resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (nic *FeilongGuestNIC) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FeilongGuestNICModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := nic.Client
+	userid := data.UserId.ValueString()
+	adapterAddress := data.AdapterAddress.ValueString()
+	osVersion := data.OSVersion.ValueString()
+	active := true
+
+	if osVersion == "" {
+		// Delete the network interface
+		deleteNICParams := feilong.DeleteGuestNICParams {
+			Active:		&active,
+		}
+		err := client.DeleteGuestNIC(userid, adapterAddress, &deleteNICParams)
+		if err != nil {
+			resp.Diagnostics.AddError("Deletion Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+	} else {
+		// Delete the network interface and its configuration in the guest
+		deleteParams := feilong.DeleteGuestNetworkInterfaceParams {
+			OSVersion:	osVersion,
+			VDev:		adapterAddress,
+			Active:		&active,
+		}
+		err := client.DeleteGuestNetworkInterface(userid, &deleteParams)
+		if err != nil {
+			resp.Diagnostics.AddError("Deletion Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Deleted the Feilong guest NIC resource")
+}
+
+// For internal use
+
+func findAdapter(client *feilong.Client, userid string, adapterAddress string) (*feilong.GetGuestAdaptersInfoAdapter, error) {
+	result, err := client.GetGuestAdaptersInfo(userid)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, adapter := range result.Output.Adapters {
+		if strings.EqualFold(adapter.AdapterAddress, adapterAddress) {
+			return &result.Output.Adapters[i], nil
+		}
+	}
+	return nil, nil
+}
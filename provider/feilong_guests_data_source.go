@@ -0,0 +1,218 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FeilongGuestsDataSource{}
+
+func NewFeilongGuestsDataSource() datasource.DataSource {
+	return &FeilongGuestsDataSource{}
+}
+
+// FeilongGuestsDataSource defines the data source implementation.
+type FeilongGuestsDataSource struct {
+	Client *feilong.Client
+}
+
+// FeilongGuestsDataSourceModel describes the data source data model.
+type FeilongGuestsDataSourceModel struct {
+	Prefix		types.String	`tfsdk:"prefix"`
+	UserIdRegex	types.String	`tfsdk:"userid_regex"`
+	PowerState	types.Bool	`tfsdk:"power_state"`
+	Addresses	types.Bool	`tfsdk:"addresses"`
+	Guests		[]FeilongGuestsDataSourceGuest `tfsdk:"guests"`
+}
+
+// FeilongGuestsDataSourceGuest describes one guest of the data source.
+type FeilongGuestsDataSourceGuest struct {
+	UserId		types.String	`tfsdk:"userid"`
+	Managed		types.Bool	`tfsdk:"managed"`
+	PowerState	types.String	`tfsdk:"power_state"`
+	MACAddress	types.String	`tfsdk:"mac_address"`
+	IPAddress	types.String	`tfsdk:"ip_address"`
+}
+
+func (guests *FeilongGuestsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_guests"
+}
+
+func (guests *FeilongGuestsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong guests data source",
+
+		Attributes: map[string]schema.Attribute {
+			"prefix": schema.StringAttribute {
+				MarkdownDescription:	"Prefix that the userids must start with",
+				Optional:		true,
+			},
+			"userid_regex": schema.StringAttribute {
+				MarkdownDescription:	"Regular expression that the userids must match",
+				Optional:		true,
+			},
+			"power_state": schema.BoolAttribute {
+				MarkdownDescription:	"Whether to look up the power state of each guest",
+				Optional:		true,
+			},
+			"addresses": schema.BoolAttribute {
+				MarkdownDescription:	"Whether to look up the addresses of the first interface of each guest",
+				Optional:		true,
+			},
+			"guests": schema.ListNestedAttribute {
+				MarkdownDescription:	"Matching guests, sorted by userid",
+				Computed:		true,
+				NestedObject:		schema.NestedAttributeObject {
+					Attributes: map[string]schema.Attribute {
+						"userid": schema.StringAttribute {
+							MarkdownDescription:	"System name for z/VM",
+							Computed:		true,
+						},
+						"managed": schema.BoolAttribute {
+							MarkdownDescription:	"Whether the guest is managed by Feilong",
+							Computed:		true,
+						},
+						"power_state": schema.StringAttribute {
+							MarkdownDescription:	"Power state (on or off), if looked up",
+							Computed:		true,
+						},
+						"mac_address": schema.StringAttribute {
+							MarkdownDescription:	"MAC address of first interface, if looked up",
+							Computed:		true,
+						},
+						"ip_address": schema.StringAttribute {
+							MarkdownDescription:	"IP address of first interface, if looked up",
+							Computed:		true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (guests *FeilongGuestsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	guests.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (guests *FeilongGuestsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeilongGuestsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var useridRegex *regexp.Regexp
+	if !data.UserIdRegex.IsNull() {
+		var err error
+		useridRegex, err = regexp.Compile(data.UserIdRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Regular Expression Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+	}
+	prefix := strings.ToUpper(data.Prefix.ValueString())
+
+	// Obtain the guests managed by Feilong
+	client := guests.Client
+	managedResult, err := client.ListGuests()
+	if err != nil {
+		resp.Diagnostics.AddError("Guest Listing Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	managed := make(map[string]bool)
+	for _, userid := range managedResult.Output {
+		managed[strings.ToUpper(userid)] = true
+	}
+
+	// Obtain all the guests on the host
+	hostResult, err := client.GetHostGuestList()
+	if err != nil {
+		resp.Diagnostics.AddError("Host Guest Listing Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	all := make(map[string]bool)
+	for _, userid := range hostResult.Output {
+		all[strings.ToUpper(userid)] = true
+	}
+	for userid := range managed {
+		all[userid] = true
+	}
+
+	// Filter the guests
+	var userids []string
+	for userid := range all {
+		if !strings.HasPrefix(userid, prefix) {
+			continue
+		}
+		if useridRegex != nil && !useridRegex.MatchString(userid) {
+			continue
+		}
+		userids = append(userids, userid)
+	}
+	sort.Strings(userids)
+
+	// Register the guests
+	data.Guests = []FeilongGuestsDataSourceGuest {}
+	for _, userid := range userids {
+		guest := FeilongGuestsDataSourceGuest {
+			UserId:		types.StringValue(userid),
+			Managed:	types.BoolValue(managed[userid]),
+			PowerState:	types.StringNull(),
+			MACAddress:	types.StringNull(),
+			IPAddress:	types.StringNull(),
+		}
+		if data.PowerState.ValueBool() {
+			// also works for guests not managed by Feilong
+			powerStateResult, err := client.GetGuestPowerStateFromHypervisor(userid)
+			if err != nil {
+				resp.Diagnostics.AddError("Power State Querying Error", fmt.Sprintf("Got error: %s", err))
+				return
+			}
+			guest.PowerState = types.StringValue(powerStateResult.Output)
+		}
+		if data.Addresses.ValueBool() {
+			adaptersInfo, err := client.GetGuestAdaptersInfo(userid)
+			if err != nil {
+				// e.g. guests not managed by Feilong, leave their addresses null
+				tflog.Warn(ctx, "Cannot get adapters of guest " + userid + ": " + err.Error())
+			} else if len(adaptersInfo.Output.Adapters) > 0 {
+				// guests without network interfaces are not an error here
+				firstAdapter := adaptersInfo.Output.Adapters[0]
+				guest.MACAddress = types.StringValue(firstAdapter.MACAddress)
+				guest.IPAddress = types.StringValue(firstAdapter.IPAddress)
+			}
+		}
+		data.Guests = append(data.Guests, guest)
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read a Feilong guests data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,246 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FeilongGuestsStatsDataSource{}
+
+func NewFeilongGuestsStatsDataSource() datasource.DataSource {
+	return &FeilongGuestsStatsDataSource{}
+}
+
+// FeilongGuestsStatsDataSource defines the data source implementation.
+type FeilongGuestsStatsDataSource struct {
+	Client *feilong.Client
+}
+
+// FeilongGuestsStatsDataSourceModel describes the data source data model.
+type FeilongGuestsStatsDataSourceModel struct {
+	UserIds		[]types.String	`tfsdk:"userids"`
+	Guests		[]FeilongGuestsStatsDataSourceGuest `tfsdk:"guests"`
+}
+
+// FeilongGuestsStatsDataSourceGuest describes the statistics of one guest.
+type FeilongGuestsStatsDataSourceGuest struct {
+	UserId		types.String	`tfsdk:"userid"`
+	GuestCPUs	types.Int64	`tfsdk:"guest_cpus"`
+	UsedCPUTime	types.Int64	`tfsdk:"used_cpu_time"`
+	ElapsedCPUTime	types.Int64	`tfsdk:"elapsed_cpu_time"`
+	MinCPUCount	types.Int64	`tfsdk:"min_cpu_count"`
+	MaxCPULimit	types.Int64	`tfsdk:"max_cpu_limit"`
+	SamplesCPUInUse	types.Int64	`tfsdk:"samples_cpu_in_use"`
+	SamplesCPUDelay	types.Int64	`tfsdk:"samples_cpu_delay"`
+	UsedMemoryKB	types.Int64	`tfsdk:"used_mem_kb"`
+	MaxMemoryKB	types.Int64	`tfsdk:"max_mem_kb"`
+	MinMemoryKB	types.Int64	`tfsdk:"min_mem_kb"`
+	SharedMemoryKB	types.Int64	`tfsdk:"shared_mem_kb"`
+	Interfaces	[]FeilongGuestsStatsDataSourceInterface `tfsdk:"interfaces"`
+}
+
+// FeilongGuestsStatsDataSourceInterface describes the statistics of one network interface.
+type FeilongGuestsStatsDataSourceInterface struct {
+	VDev		types.String	`tfsdk:"vdev"`
+	VSwitch		types.String	`tfsdk:"vswitch"`
+	FramesReceived	types.Int64	`tfsdk:"frames_received"`
+	FramesSent	types.Int64	`tfsdk:"frames_sent"`
+	FramesReceivedDiscarded types.Int64 `tfsdk:"frames_received_discarded"`
+	FramesSentDiscarded types.Int64	`tfsdk:"frames_sent_discarded"`
+	FramesReceivedErrors types.Int64 `tfsdk:"frames_received_errors"`
+	FramesSentErrors types.Int64	`tfsdk:"frames_sent_errors"`
+	BytesReceived	types.Int64	`tfsdk:"bytes_received"`
+	BytesSent	types.Int64	`tfsdk:"bytes_sent"`
+}
+
+func (stats *FeilongGuestsStatsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_guests_stats"
+}
+
+func (stats *FeilongGuestsStatsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	counter := func(description string) schema.Int64Attribute {
+		return schema.Int64Attribute {
+			MarkdownDescription:	description,
+			Computed:		true,
+		}
+	}
+
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong guests statistics data source",
+
+		Attributes: map[string]schema.Attribute {
+			"userids": schema.ListAttribute {
+				MarkdownDescription:	"System names for z/VM of the guests",
+				ElementType:		types.StringType,
+				Required:		true,
+			},
+			"guests": schema.ListNestedAttribute {
+				MarkdownDescription:	"Statistics of the guests, sorted by userid",
+				Computed:		true,
+				NestedObject:		schema.NestedAttributeObject {
+					Attributes: map[string]schema.Attribute {
+						"userid": schema.StringAttribute {
+							MarkdownDescription:	"System name for z/VM",
+							Computed:		true,
+						},
+						"guest_cpus":		counter("Virtual CPUs count"),
+						"used_cpu_time":	counter("Used CPU time in microseconds"),
+						"elapsed_cpu_time":	counter("Elapsed CPU time in microseconds"),
+						"min_cpu_count":	counter("Minimal CPU count"),
+						"max_cpu_limit":	counter("Maximal CPU limit"),
+						"samples_cpu_in_use":	counter("Count of samples where the CPU was in use"),
+						"samples_cpu_delay":	counter("Count of samples where the CPU was delayed"),
+						"used_mem_kb":		counter("Used memory in kilobytes"),
+						"max_mem_kb":		counter("Maximal memory in kilobytes"),
+						"min_mem_kb":		counter("Minimal memory in kilobytes"),
+						"shared_mem_kb":	counter("Shared memory in kilobytes"),
+						"interfaces": schema.ListNestedAttribute {
+							MarkdownDescription:	"Statistics of the network interfaces",
+							Computed:		true,
+							NestedObject:		schema.NestedAttributeObject {
+								Attributes: map[string]schema.Attribute {
+									"vdev": schema.StringAttribute {
+										MarkdownDescription:	"Virtual device of the interface",
+										Computed:		true,
+									},
+									"vswitch": schema.StringAttribute {
+										MarkdownDescription:	"Name of virtual switch the interface is connected to",
+										Computed:		true,
+									},
+									"frames_received":		counter("Count of received frames"),
+									"frames_sent":			counter("Count of sent frames"),
+									"frames_received_discarded":	counter("Count of discarded received frames"),
+									"frames_sent_discarded":	counter("Count of discarded sent frames"),
+									"frames_received_errors":	counter("Count of received frames with errors"),
+									"frames_sent_errors":		counter("Count of sent frames with errors"),
+									"bytes_received":		counter("Count of received bytes"),
+									"bytes_sent":			counter("Count of sent bytes"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (stats *FeilongGuestsStatsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	stats.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (stats *FeilongGuestsStatsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeilongGuestsStatsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Feilong accepts a comma-separated list of userids
+	var userids []string
+	for _, userid := range data.UserIds {
+		userids = append(userids, strings.ToUpper(userid.ValueString()))
+	}
+	sort.Strings(userids)
+	useridList := strings.Join(userids, ",")
+
+	// Obtain CPU and memory statistics
+	client := stats.Client
+	guestsStats, err := client.GetGuestsStats(useridList)
+	if err != nil {
+		resp.Diagnostics.AddError("Guest Statistics Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Obtain network interface statistics
+	interfacesStats, err := client.GetGuestsInterfaceStats(useridList)
+	if err != nil {
+		resp.Diagnostics.AddError("Interface Statistics Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Register the statistics
+	data.Guests = []FeilongGuestsStatsDataSourceGuest {}
+	for _, userid := range userids {
+		s, found := guestsStats.Output[userid]
+		if !found {
+			// typically a guest that is powered off
+			tflog.Warn(ctx, "No statistics for guest " + userid + ", leaving its counters empty")
+			data.Guests = append(data.Guests, FeilongGuestsStatsDataSourceGuest {
+				UserId:		types.StringValue(userid),
+				GuestCPUs:	types.Int64Null(),
+				UsedCPUTime:	types.Int64Null(),
+				ElapsedCPUTime:	types.Int64Null(),
+				MinCPUCount:	types.Int64Null(),
+				MaxCPULimit:	types.Int64Null(),
+				SamplesCPUInUse: types.Int64Null(),
+				SamplesCPUDelay: types.Int64Null(),
+				UsedMemoryKB:	types.Int64Null(),
+				MaxMemoryKB:	types.Int64Null(),
+				MinMemoryKB:	types.Int64Null(),
+				SharedMemoryKB:	types.Int64Null(),
+				Interfaces:	[]FeilongGuestsStatsDataSourceInterface {},
+			})
+			continue
+		}
+		guest := FeilongGuestsStatsDataSourceGuest {
+			UserId:		types.StringValue(userid),
+			GuestCPUs:	types.Int64Value(int64(s.GuestCPUs)),
+			UsedCPUTime:	types.Int64Value(int64(s.UsedCPUTime)),
+			ElapsedCPUTime:	types.Int64Value(int64(s.ElapsedCPUTime)),
+			MinCPUCount:	types.Int64Value(int64(s.MinCPUCount)),
+			MaxCPULimit:	types.Int64Value(int64(s.MaxCPULimit)),
+			SamplesCPUInUse: types.Int64Value(int64(s.SamplesCPUInUse)),
+			SamplesCPUDelay: types.Int64Value(int64(s.SamplesCPUDelay)),
+			UsedMemoryKB:	types.Int64Value(int64(s.UsedMemoryKB)),
+			MaxMemoryKB:	types.Int64Value(int64(s.MaxMemoryKB)),
+			MinMemoryKB:	types.Int64Value(int64(s.MinMemoryKB)),
+			SharedMemoryKB:	types.Int64Value(int64(s.SharedMemoryKB)),
+			Interfaces:	[]FeilongGuestsStatsDataSourceInterface {},
+		}
+		for _, i := range interfacesStats.Output[userid] {
+			guest.Interfaces = append(guest.Interfaces, FeilongGuestsStatsDataSourceInterface {
+				VDev:			types.StringValue(i.VDev),
+				VSwitch:		types.StringValue(i.VSwitch),
+				FramesReceived:		types.Int64Value(int64(i.FramesRec)),
+				FramesSent:		types.Int64Value(int64(i.FramesSent)),
+				FramesReceivedDiscarded: types.Int64Value(int64(i.FramesRecDisc)),
+				FramesSentDiscarded:	types.Int64Value(int64(i.FramesSentDisc)),
+				FramesReceivedErrors:	types.Int64Value(int64(i.FramesRecErr)),
+				FramesSentErrors:	types.Int64Value(int64(i.FramesSentErr)),
+				BytesReceived:		types.Int64Value(int64(i.BytesRec)),
+				BytesSent:		types.Int64Value(int64(i.BytesSent)),
+			})
+		}
+		data.Guests = append(data.Guests, guest)
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read a Feilong guests statistics data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,186 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FeilongHostDataSource{}
+
+func NewFeilongHostDataSource() datasource.DataSource {
+	return &FeilongHostDataSource{}
+}
+
+// FeilongHostDataSource defines the data source implementation.
+type FeilongHostDataSource struct {
+	Client *feilong.Client
+}
+
+// FeilongHostDataSourceModel describes the data source data model.
+type FeilongHostDataSourceModel struct {
+	ZVMHost		types.String	`tfsdk:"zvm_host"`
+	ZCCUserId	types.String	`tfsdk:"zcc_userid"`
+	HypervisorHostname types.String	`tfsdk:"hypervisor_hostname"`
+	HypervisorType	types.String	`tfsdk:"hypervisor_type"`
+	HypervisorVersion types.Int64	`tfsdk:"hypervisor_version"`
+	IPLTime		types.String	`tfsdk:"ipl_time"`
+	CECModel	types.String	`tfsdk:"cec_model"`
+	Architecture	types.String	`tfsdk:"architecture"`
+	VCPUs		types.Int64	`tfsdk:"vcpus"`
+	VCPUsUsed	types.Int64	`tfsdk:"vcpus_used"`
+	VCPUsFree	types.Int64	`tfsdk:"vcpus_free"`
+	MemoryMB	types.Int64	`tfsdk:"memory_mb"`
+	MemoryMBUsed	types.Int64	`tfsdk:"memory_mb_used"`
+	MemoryMBFree	types.Int64	`tfsdk:"memory_mb_free"`
+	DiskTotal	types.Int64	`tfsdk:"disk_total"`
+	DiskUsed	types.Int64	`tfsdk:"disk_used"`
+	DiskAvailable	types.Int64	`tfsdk:"disk_available"`
+}
+
+func (host *FeilongHostDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_host"
+}
+
+func (host *FeilongHostDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong z/VM host data source",
+
+		Attributes: map[string]schema.Attribute {
+			"zvm_host": schema.StringAttribute {
+				MarkdownDescription:	"Name of the z/VM host",
+				Computed:		true,
+			},
+			"zcc_userid": schema.StringAttribute {
+				MarkdownDescription:	"System name for z/VM of the z/VM cloud connector",
+				Computed:		true,
+			},
+			"hypervisor_hostname": schema.StringAttribute {
+				MarkdownDescription:	"Host name of the hypervisor",
+				Computed:		true,
+			},
+			"hypervisor_type": schema.StringAttribute {
+				MarkdownDescription:	"Type of the hypervisor, e.g. zvm",
+				Computed:		true,
+			},
+			"hypervisor_version": schema.Int64Attribute {
+				MarkdownDescription:	"Version of the hypervisor, e.g. 720",
+				Computed:		true,
+			},
+			"ipl_time": schema.StringAttribute {
+				MarkdownDescription:	"Time of the last initial program load of the hypervisor",
+				Computed:		true,
+			},
+			"cec_model": schema.StringAttribute {
+				MarkdownDescription:	"Model of the central electronic complex",
+				Computed:		true,
+			},
+			"architecture": schema.StringAttribute {
+				MarkdownDescription:	"Processor architecture, e.g. s390x",
+				Computed:		true,
+			},
+			"vcpus": schema.Int64Attribute {
+				MarkdownDescription:	"Total virtual CPUs count",
+				Computed:		true,
+			},
+			"vcpus_used": schema.Int64Attribute {
+				MarkdownDescription:	"Used virtual CPUs count",
+				Computed:		true,
+			},
+			"vcpus_free": schema.Int64Attribute {
+				MarkdownDescription:	"Free virtual CPUs count",
+				Computed:		true,
+			},
+			"memory_mb": schema.Int64Attribute {
+				MarkdownDescription:	"Total memory size in megabytes",
+				Computed:		true,
+			},
+			"memory_mb_used": schema.Int64Attribute {
+				MarkdownDescription:	"Used memory size in megabytes",
+				Computed:		true,
+			},
+			"memory_mb_free": schema.Int64Attribute {
+				MarkdownDescription:	"Free memory size in megabytes",
+				Computed:		true,
+			},
+			"disk_total": schema.Int64Attribute {
+				MarkdownDescription:	"Total disk size in gigabytes",
+				Computed:		true,
+			},
+			"disk_used": schema.Int64Attribute {
+				MarkdownDescription:	"Used disk size in gigabytes",
+				Computed:		true,
+			},
+			"disk_available": schema.Int64Attribute {
+				MarkdownDescription:	"Available disk size in gigabytes",
+				Computed:		true,
+			},
+		},
+	}
+}
+
+func (host *FeilongHostDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	host.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (host *FeilongHostDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeilongHostDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Obtain info about the host
+	result, err := host.Client.GetHostInfo()
+	if err != nil {
+		resp.Diagnostics.AddError("Host Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	info := result.Output
+
+	data.ZVMHost = types.StringValue(info.ZVMHost)
+	data.ZCCUserId = types.StringValue(info.ZCCUserID)
+	data.HypervisorHostname = types.StringValue(info.HypervisorHostname)
+	data.HypervisorType = types.StringValue(info.HypervisorType)
+	data.HypervisorVersion = types.Int64Value(int64(info.HypervisorVersion))
+	data.IPLTime = types.StringValue(info.IPLTime)
+	data.CECModel = types.StringValue(info.CPUInfo.CECModel)
+	data.Architecture = types.StringValue(info.CPUInfo.Architecture)
+
+	// Compute free values
+	data.VCPUs = types.Int64Value(int64(info.VCPUs))
+	data.VCPUsUsed = types.Int64Value(int64(info.VCPUsUsed))
+	data.VCPUsFree = types.Int64Value(int64(info.VCPUs - info.VCPUsUsed))
+	data.MemoryMB = types.Int64Value(int64(info.MemoryMB))
+	data.MemoryMBUsed = types.Int64Value(int64(info.MemoryMBUsed))
+	data.MemoryMBFree = types.Int64Value(int64(info.MemoryMB - info.MemoryMBUsed))
+	data.DiskTotal = types.Int64Value(int64(info.DiskTotal))
+	data.DiskUsed = types.Int64Value(int64(info.DiskUsed))
+	data.DiskAvailable = types.Int64Value(int64(info.DiskAvailable))
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read a Feilong host data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,398 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &FeilongImage{}
+var _ resource.ResourceWithModifyPlan = &FeilongImage{}
+
+func NewFeilongImage() resource.Resource {
+	return &FeilongImage{}
+}
+
+// FeilongImage defines the resource implementation.
+type FeilongImage struct {
+	Client *feilong.Client
+	LocalUser string
+}
+
+// FeilongImageModel describes the resource data model.
+type FeilongImageModel struct {
+	Name		types.String	`tfsdk:"name"`
+	ImageName	types.String	`tfsdk:"image_name"`
+	URL		types.String	`tfsdk:"url"`
+	File		types.String	`tfsdk:"file"`
+	OSVersion	types.String	`tfsdk:"os_version"`
+	DiskType	types.String	`tfsdk:"disk_type"`
+	MD5Sum		types.String	`tfsdk:"md5sum"`
+	ImageSizeInBytes types.Int64	`tfsdk:"image_size_in_bytes"`
+	DiskSizeUnits	types.String	`tfsdk:"disk_size_units"`
+	ImageOSDistro	types.String	`tfsdk:"image_os_distro"`
+}
+
+func (image *FeilongImage) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_image"
+}
+
+func (image *FeilongImage) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong image resource",
+
+		Attributes: map[string]schema.Attribute {
+			"name": schema.StringAttribute {
+				MarkdownDescription:	"Arbitrary name for the resource",
+				Required:		true,
+			},
+			"image_name": schema.StringAttribute {
+				MarkdownDescription:	"Image name on the z/VM connector",
+				Optional:		true,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"url": schema.StringAttribute {
+				MarkdownDescription:	"URL of the image source, e.g. http://server/image.img",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"file": schema.StringAttribute {
+				MarkdownDescription:	"Path to a local image file, uploaded from the local user's host",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"os_version": schema.StringAttribute {
+				MarkdownDescription:	"Operating system version of the image, e.g. sles15.7",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"disk_type": schema.StringAttribute {
+				MarkdownDescription:	"Disk type of the image (DASD or SCSI)",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"md5sum": schema.StringAttribute {
+				MarkdownDescription:	"MD5 checksum of the image source; computed from the local file when not given",
+				Optional:		true,
+				Computed:		true,
+			},
+			"image_size_in_bytes": schema.Int64Attribute {
+				MarkdownDescription:	"Size of the image in bytes",
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.Int64 {
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"disk_size_units": schema.StringAttribute {
+				MarkdownDescription:	"Size of the root disk of the image, with units",
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"image_os_distro": schema.StringAttribute {
+				MarkdownDescription:	"Operating system distribution of the image as reported by z/VM connector",
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (image *FeilongImage) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	image.Client = &req.ProviderData.(*apiClient).Client
+	image.LocalUser = req.ProviderData.(*apiClient).LocalUser
+}
+
+func (image *FeilongImage) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var config FeilongImageModel
+	var state FeilongImageModel
+
+	// Nothing to do on destruction
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Compute the checksum of the image source
+	md5sum := config.MD5Sum
+	if md5sum.IsNull() {
+		file := config.File.ValueString()
+		if file == "" || config.File.IsUnknown() {
+			md5sum = types.StringUnknown()
+		} else {
+			sum, err := computeMD5Sum(file)
+			if err != nil {
+				resp.Diagnostics.AddError("Checksum Computation Error", fmt.Sprintf("Got error: %s", err))
+				return
+			}
+			md5sum = types.StringValue(sum)
+		}
+	}
+
+	// On creation, there is no previous checksum to compare with
+	if req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("md5sum"), md5sum)...)
+		return
+	}
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Replace the image if its source changed
+	if md5sum.IsUnknown() && config.MD5Sum.IsNull() {
+		// no way to know the checksum of a remote image in advance
+		md5sum = state.MD5Sum
+	} else if md5sum.IsUnknown() || md5sum.ValueString() != state.MD5Sum.ValueString() {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("md5sum"))
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("md5sum"), md5sum)...)
+}
+
+func (image *FeilongImage) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FeilongImageModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Compute computed fields
+	imageName := data.ImageName.ValueString()
+	if imageName == "" {
+		imageName = data.Name.ValueString()
+		data.ImageName = types.StringValue(imageName)
+	}
+
+	// Compute values passed to Feilong API but not part of the data model
+	url := data.URL.ValueString()
+	file := data.File.ValueString()
+	remoteHost := ""
+	if (url == "") == (file == "") {
+		resp.Diagnostics.AddError("Invalid Image Source", "Exactly one of \"url\" or \"file\" must be given")
+		return
+	}
+	if file != "" {
+		absPath, err := filepath.Abs(file)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Image Source", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+		url = "file://" + absPath
+		remoteHost = image.LocalUser
+	}
+	md5sum := data.MD5Sum.ValueString()
+
+	// Upload and register the image
+	client := image.Client
+	createParams := feilong.CreateImageParams {
+		ImageName:	imageName,
+		URL:		url,
+		ImageMeta:	feilong.CreateImageMeta {
+			OSVersion:	data.OSVersion.ValueString(),
+			MD5Sum:		md5sum,
+			DiskType:	data.DiskType.ValueString(),
+		},
+		RemoteHost:	remoteHost,
+	}
+	err := client.CreateImage(&createParams)
+	if err != nil {
+		resp.Diagnostics.AddError("Creation Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Get computed values
+	found, err := readImage(client, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Image Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError("Image Not Found Error", fmt.Sprintf("Image \"%s\" not listed after creation", imageName))
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Created a Feilong image resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (image *FeilongImage) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FeilongImageModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Obtain info about this image
+	found, err := readImage(image.Client, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Image Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	if !found {
+		// the image was deleted outside of terraform
+		tflog.Info(ctx, "Image " + data.ImageName.ValueString() + " not found, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// CAVEATS:
+	//  - the URL or file used to upload the image cannot be determined after the upload
+	//  - the disk type cannot be determined after the upload
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read characteristics of Feilong image resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (image *FeilongImage) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data FeilongImageModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// All attributes of the image itself require a replacement,
+	// so only the resource name can change here
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Updated Feilong image resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (image *FeilongImage) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FeilongImageModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := image.Client
+	imageName := data.ImageName.ValueString()
+
+	// Delete the image
+	err := client.DeleteImage(imageName)
+	if err != nil {
+		resp.Diagnostics.AddError("Deletion Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Deleted the Feilong image resource")
+}
+
+// For internal use
+
+func computeMD5Sum(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := md5.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func findImage(client *feilong.Client, imageName string) (*feilong.ListImagesImage, error) {
+	// querying an unknown image name is an error, so list them all
+	result, err := client.ListImages(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, image := range result.Output {
+		if image.ImageName == imageName {
+			return &image, nil
+		}
+	}
+	return nil, nil
+}
+
+func readImage(client *feilong.Client, data *FeilongImageModel) (bool, error) {
+	image, err := findImage(client, data.ImageName.ValueString())
+	if err != nil || image == nil {
+		return false, err
+	}
+
+	size, err := strconv.ParseInt(image.ImageSizeInBytes, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	data.ImageSizeInBytes = types.Int64Value(size)
+	data.DiskSizeUnits = types.StringValue(image.DiskSizeUnits)
+	data.ImageOSDistro = types.StringValue(image.ImageOSDistro)
+	if image.MD5Sum != "" {
+		data.MD5Sum = types.StringValue(image.MD5Sum)
+	} else if data.MD5Sum.IsUnknown() {
+		// the connector did not report any checksum and none was declared
+		data.MD5Sum = types.StringNull()
+	}
+	return true, nil
+}
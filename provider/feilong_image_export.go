@@ -0,0 +1,250 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &FeilongImageExport{}
+
+func NewFeilongImageExport() resource.Resource {
+	return &FeilongImageExport{}
+}
+
+// FeilongImageExport defines the resource implementation.
+type FeilongImageExport struct {
+	Client *feilong.Client
+}
+
+// FeilongImageExportModel describes the resource data model.
+type FeilongImageExportModel struct {
+	Name		types.String	`tfsdk:"name"`
+	ImageName	types.String	`tfsdk:"image_name"`
+	DestURL		types.String	`tfsdk:"dest_url"`
+	File		types.String	`tfsdk:"file"`
+	OSVersion	types.String	`tfsdk:"os_version"`
+	MD5Sum		types.String	`tfsdk:"md5sum"`
+	Size		types.Int64	`tfsdk:"size"`
+}
+
+func (export *FeilongImageExport) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_image_export"
+}
+
+func (export *FeilongImageExport) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong image export resource",
+
+		Attributes: map[string]schema.Attribute {
+			"name": schema.StringAttribute {
+				MarkdownDescription:	"Arbitrary name for the resource",
+				Required:		true,
+			},
+			"image_name": schema.StringAttribute {
+				MarkdownDescription:	"Name of the image to export",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"dest_url": schema.StringAttribute {
+				MarkdownDescription:	"Where to stage the exported image on the z/VM connector, e.g. file:///var/lib/zvmsdk/export",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"file": schema.StringAttribute {
+				MarkdownDescription:	"Local path where to download the exported image",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"os_version": schema.StringAttribute {
+				MarkdownDescription:	"Operating system version of the exported image",
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"md5sum": schema.StringAttribute {
+				MarkdownDescription:	"MD5 checksum of the exported image",
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"size": schema.Int64Attribute {
+				MarkdownDescription:	"Size of the exported image in bytes",
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.Int64 {
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (export *FeilongImageExport) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	export.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (export *FeilongImageExport) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FeilongImageExportModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Export the image on the z/VM connector
+	client := export.Client
+	exportParams := feilong.ExportImageParams {
+		DestURL:	data.DestURL.ValueString(),
+	}
+	result, err := client.ExportImage(data.ImageName.ValueString(), &exportParams)
+	if err != nil {
+		resp.Diagnostics.AddError("Export Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Download the exported image
+	fileParams := feilong.ExportFileParams {
+		SourceFile:	result.Output.ImagePath,
+	}
+	fileResult, err := client.ExportFile(&fileParams)
+	if err != nil {
+		resp.Diagnostics.AddError("Download Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Verify the checksum
+	hash := md5.Sum(fileResult.Contents)
+	md5sum := hex.EncodeToString(hash[:])
+	if result.Output.MD5Sum != "" && !strings.EqualFold(md5sum, result.Output.MD5Sum) {
+		resp.Diagnostics.AddError("Checksum Mismatch Error", fmt.Sprintf("Expected MD5 checksum %s, got %s", result.Output.MD5Sum, md5sum))
+		return
+	}
+
+	// Save the image locally
+	err = os.WriteFile(data.File.ValueString(), fileResult.Contents, 0644)
+	if err != nil {
+		resp.Diagnostics.AddError("File Writing Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Register the result
+	data.OSVersion = types.StringValue(result.Output.OSVersion)
+	data.MD5Sum = types.StringValue(md5sum)
+	data.Size = types.Int64Value(int64(len(fileResult.Contents)))
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Created a Feilong image export resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (export *FeilongImageExport) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FeilongImageExportModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Check that the local copy is still intact
+	file := data.File.ValueString()
+	md5sum, err := computeMD5Sum(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		tflog.Info(ctx, "Exported image " + file + " not found, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Checksum Computation Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	if md5sum != data.MD5Sum.ValueString() {
+		tflog.Info(ctx, "Exported image " + file + " was modified, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read characteristics of Feilong image export resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (export *FeilongImageExport) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data FeilongImageExportModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// All attributes of the export itself require a replacement,
+	// so only the resource name can change here
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Updated Feilong image export resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (export *FeilongImageExport) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FeilongImageExportModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Remove the local copy
+	// (Feilong offers no way to remove the copy staged on the z/VM connector)
+	err := os.Remove(data.File.ValueString())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		resp.Diagnostics.AddError("Deletion Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Deleted the Feilong image export resource")
+}
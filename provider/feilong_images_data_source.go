@@ -0,0 +1,232 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FeilongImagesDataSource{}
+
+func NewFeilongImagesDataSource() datasource.DataSource {
+	return &FeilongImagesDataSource{}
+}
+
+// FeilongImagesDataSource defines the data source implementation.
+type FeilongImagesDataSource struct {
+	Client *feilong.Client
+}
+
+// FeilongImagesDataSourceModel describes the data source data model.
+type FeilongImagesDataSourceModel struct {
+	NameRegex	types.String	`tfsdk:"name_regex"`
+	OSDistro	types.String	`tfsdk:"os_distro"`
+	Type		types.String	`tfsdk:"type"`
+	MostRecent	types.Bool	`tfsdk:"most_recent"`
+	RootDiskSize	types.Bool	`tfsdk:"root_disk_size"`
+	Images		[]FeilongImagesDataSourceImage `tfsdk:"images"`
+}
+
+// FeilongImagesDataSourceImage describes one image of the data source.
+type FeilongImagesDataSourceImage struct {
+	ImageName	types.String	`tfsdk:"image_name"`
+	ImageOSDistro	types.String	`tfsdk:"image_os_distro"`
+	MD5Sum		types.String	`tfsdk:"md5sum"`
+	DiskSizeUnits	types.String	`tfsdk:"disk_size_units"`
+	ImageSizeInBytes types.Int64	`tfsdk:"image_size_in_bytes"`
+	Type		types.String	`tfsdk:"type"`
+	Comments	types.String	`tfsdk:"comments"`
+	LastAccessTime	types.Float64	`tfsdk:"last_access_time"`
+	RootDiskSize	types.String	`tfsdk:"root_disk_size"`
+}
+
+func (images *FeilongImagesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_images"
+}
+
+func (images *FeilongImagesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong images data source",
+
+		Attributes: map[string]schema.Attribute {
+			"name_regex": schema.StringAttribute {
+				MarkdownDescription:	"Regular expression that the image names must match",
+				Optional:		true,
+			},
+			"os_distro": schema.StringAttribute {
+				MarkdownDescription:	"Operating system distribution of the images, e.g. sles15",
+				Optional:		true,
+			},
+			"type": schema.StringAttribute {
+				MarkdownDescription:	"Type of the images, e.g. rootonly",
+				Optional:		true,
+			},
+			"most_recent": schema.BoolAttribute {
+				MarkdownDescription:	"Whether to return only the most recently accessed image, and fail if none matches",
+				Optional:		true,
+			},
+			"root_disk_size": schema.BoolAttribute {
+				MarkdownDescription:	"Whether to look up the root disk size of each image",
+				Optional:		true,
+			},
+			"images": schema.ListNestedAttribute {
+				MarkdownDescription:	"Matching images, most recently accessed first",
+				Computed:		true,
+				NestedObject:		schema.NestedAttributeObject {
+					Attributes: map[string]schema.Attribute {
+						"image_name": schema.StringAttribute {
+							MarkdownDescription:	"Name of the image on the z/VM connector",
+							Computed:		true,
+						},
+						"image_os_distro": schema.StringAttribute {
+							MarkdownDescription:	"Operating system distribution of the image",
+							Computed:		true,
+						},
+						"md5sum": schema.StringAttribute {
+							MarkdownDescription:	"MD5 checksum of the image",
+							Computed:		true,
+						},
+						"disk_size_units": schema.StringAttribute {
+							MarkdownDescription:	"Size of the root disk of the image, with units",
+							Computed:		true,
+						},
+						"image_size_in_bytes": schema.Int64Attribute {
+							MarkdownDescription:	"Size of the image in bytes",
+							Computed:		true,
+						},
+						"type": schema.StringAttribute {
+							MarkdownDescription:	"Type of the image",
+							Computed:		true,
+						},
+						"comments": schema.StringAttribute {
+							MarkdownDescription:	"Comments about the image",
+							Computed:		true,
+						},
+						"last_access_time": schema.Float64Attribute {
+							MarkdownDescription:	"Time of last access to the image, in seconds since the epoch",
+							Computed:		true,
+						},
+						"root_disk_size": schema.StringAttribute {
+							MarkdownDescription:	"Size of the root disk of the image, if looked up",
+							Computed:		true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (images *FeilongImagesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	images.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (images *FeilongImagesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeilongImagesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !data.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(data.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Regular Expression Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+	}
+
+	// Obtain the list of images
+	client := images.Client
+	result, err := client.ListImages(nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Image Listing Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Filter the images
+	var found []feilong.ListImagesImage
+	for _, image := range result.Output {
+		if nameRegex != nil && !nameRegex.MatchString(image.ImageName) {
+			continue
+		}
+		if !data.OSDistro.IsNull() && image.ImageOSDistro != data.OSDistro.ValueString() {
+			continue
+		}
+		if !data.Type.IsNull() && image.Type != data.Type.ValueString() {
+			continue
+		}
+		found = append(found, image)
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].LastAccessTime > found[j].LastAccessTime
+	})
+	if data.MostRecent.ValueBool() {
+		if len(found) == 0 {
+			resp.Diagnostics.AddError("Image Not Found Error", "No image matches the given filters")
+			return
+		}
+		found = found[:1]
+	}
+
+	// Register the images
+	data.Images = []FeilongImagesDataSourceImage {}
+	for _, image := range found {
+		size, err := strconv.ParseInt(image.ImageSizeInBytes, 10, 64)
+		if err != nil {
+			resp.Diagnostics.AddError("Image Size Conversion Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+		rootDiskSize := types.StringNull()
+		if data.RootDiskSize.ValueBool() {
+			sizeResult, err := client.GetImageRootDiskSize(image.ImageName)
+			if err != nil {
+				resp.Diagnostics.AddError("Root Disk Size Querying Error", fmt.Sprintf("Got error: %s", err))
+				return
+			}
+			rootDiskSize = types.StringValue(sizeResult.Output)
+		}
+		data.Images = append(data.Images, FeilongImagesDataSourceImage {
+			ImageName:		types.StringValue(image.ImageName),
+			ImageOSDistro:		types.StringValue(image.ImageOSDistro),
+			MD5Sum:			types.StringValue(image.MD5Sum),
+			DiskSizeUnits:		types.StringValue(image.DiskSizeUnits),
+			ImageSizeInBytes:	types.Int64Value(size),
+			Type:			types.StringValue(image.Type),
+			Comments:		types.StringValue(image.Comments),
+			LastAccessTime:		types.Float64Value(image.LastAccessTime),
+			RootDiskSize:		rootDiskSize,
+		})
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read a Feilong images data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
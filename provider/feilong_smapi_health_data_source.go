@@ -0,0 +1,138 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FeilongSMAPIHealthDataSource{}
+
+func NewFeilongSMAPIHealthDataSource() datasource.DataSource {
+	return &FeilongSMAPIHealthDataSource{}
+}
+
+// FeilongSMAPIHealthDataSource defines the data source implementation.
+type FeilongSMAPIHealthDataSource struct {
+	Client *feilong.Client
+}
+
+// FeilongSMAPIHealthDataSourceModel describes the data source data model.
+type FeilongSMAPIHealthDataSourceModel struct {
+	Healthy		types.Bool	`tfsdk:"healthy"`
+	TotalSuccess	types.Int64	`tfsdk:"total_success"`
+	TotalFail	types.Int64	`tfsdk:"total_fail"`
+	LastSuccess	types.String	`tfsdk:"last_success"`
+	LastFail	types.String	`tfsdk:"last_fail"`
+	ContinuousFail	types.Int64	`tfsdk:"continuous_fail"`
+}
+
+func (health *FeilongSMAPIHealthDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_smapi_health"
+}
+
+func (health *FeilongSMAPIHealthDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong SMAPI health data source",
+
+		Attributes: map[string]schema.Attribute {
+			"healthy": schema.BoolAttribute {
+				MarkdownDescription:	"Whether SMAPI is healthy, if reported",
+				Computed:		true,
+			},
+			"total_success": schema.Int64Attribute {
+				MarkdownDescription:	"Total count of successful SMAPI calls",
+				Computed:		true,
+			},
+			"total_fail": schema.Int64Attribute {
+				MarkdownDescription:	"Total count of failed SMAPI calls",
+				Computed:		true,
+			},
+			"last_success": schema.StringAttribute {
+				MarkdownDescription:	"Time of last successful SMAPI call",
+				Computed:		true,
+			},
+			"last_fail": schema.StringAttribute {
+				MarkdownDescription:	"Time of last failed SMAPI call",
+				Computed:		true,
+			},
+			"continuous_fail": schema.Int64Attribute {
+				MarkdownDescription:	"Count of SMAPI calls that failed since last successful one",
+				Computed:		true,
+			},
+		},
+	}
+}
+
+func (health *FeilongSMAPIHealthDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	health.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (health *FeilongSMAPIHealthDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeilongSMAPIHealthDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Obtain SMAPI health
+	output, err := getSMAPIHealth(ctx, health.Client)
+	if err != nil {
+		resp.Diagnostics.AddError("SMAPI Health Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	if output.Healthy == nil {
+		data.Healthy = types.BoolNull()
+	} else {
+		data.Healthy = types.BoolValue(*output.Healthy)
+	}
+	data.TotalSuccess = types.Int64Value(int64(output.TotalSuccess))
+	data.TotalFail = types.Int64Value(int64(output.TotalFail))
+	data.LastSuccess = types.StringValue(output.LastSuccess)
+	data.LastFail = types.StringValue(output.LastFail)
+	data.ContinuousFail = types.Int64Value(int64(output.ContinuousFail))
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read a Feilong SMAPI health data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// For internal use
+
+func getSMAPIHealth(ctx context.Context, client *feilong.Client) (*feilong.SMAPIHealthOutput, error) {
+	result, err := client.SMAPIHealth()
+	if err == nil {
+		return &result.Output, nil
+	}
+
+	// older connectors only know the deprecated call
+	tflog.Info(ctx, "SMAPI health call failed, falling back to deprecated call: " + err.Error())
+	oldResult, oldErr := client.SMAPIHealthy()
+	if oldErr != nil {
+		return nil, err
+	}
+	return &oldResult.SMAPI, nil
+}
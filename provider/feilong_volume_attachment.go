@@ -0,0 +1,340 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &FeilongVolumeAttachment{}
+
+func NewFeilongVolumeAttachment() resource.Resource {
+	return &FeilongVolumeAttachment{}
+}
+
+// FeilongVolumeAttachment defines the resource implementation.
+type FeilongVolumeAttachment struct {
+	Client *feilong.Client
+}
+
+// FeilongVolumeAttachmentModel describes the resource data model.
+type FeilongVolumeAttachmentModel struct {
+	Name		types.String	`tfsdk:"name"`
+	UserId		types.String	`tfsdk:"userid"`
+	OSVersion	types.String	`tfsdk:"os_version"`
+	TargetWWPNs	types.List	`tfsdk:"target_wwpns"`
+	LUN		types.String	`tfsdk:"lun"`
+	FCPTemplateId	types.String	`tfsdk:"fcp_template_id"`
+	StorageProvider	types.String	`tfsdk:"storage_provider"`
+	Multipath	types.Bool	`tfsdk:"multipath"`
+	MountPoint	types.String	`tfsdk:"mount_point"`
+	DoRollback	types.Bool	`tfsdk:"do_rollback"`
+	FCPDevices	types.List	`tfsdk:"fcp_devices"`
+}
+
+func (attachment *FeilongVolumeAttachment) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_volume_attachment"
+}
+
+func (attachment *FeilongVolumeAttachment) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong SAN volume attachment resource",
+
+		Attributes: map[string]schema.Attribute {
+			"name": schema.StringAttribute {
+				MarkdownDescription:	"Arbitrary name for the resource",
+				Required:		true,
+			},
+			"userid": schema.StringAttribute {
+				MarkdownDescription:	"System name for z/VM of the guest to attach the volume to",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"os_version": schema.StringAttribute {
+				MarkdownDescription:	"Operating system version of the guest, e.g. the os_version of a feilong_guest",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"target_wwpns": schema.ListAttribute {
+				MarkdownDescription:	"World wide port names of the storage target",
+				ElementType:		types.StringType,
+				Required:		true,
+				PlanModifiers:		[]planmodifier.List {
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"lun": schema.StringAttribute {
+				MarkdownDescription:	"Logical unit number of the volume",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"fcp_template_id": schema.StringAttribute {
+				MarkdownDescription:	"Identifier of the FCP template to allocate FCP devices from",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"storage_provider": schema.StringAttribute {
+				MarkdownDescription:	"Storage provider used to select the default FCP template",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"multipath": schema.BoolAttribute {
+				MarkdownDescription:	"Whether to use multiple paths to the volume",
+				Optional:		true,
+				Computed:		true,
+				Default:		booldefault.StaticBool(false),
+				PlanModifiers:		[]planmodifier.Bool {
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"mount_point": schema.StringAttribute {
+				MarkdownDescription:	"Directory where to mount the volume in the guest",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"do_rollback": schema.BoolAttribute {
+				MarkdownDescription:	"Whether to undo a partial attachment or detachment on failure",
+				Optional:		true,
+				Computed:		true,
+				Default:		booldefault.StaticBool(true),
+			},
+			"fcp_devices": schema.ListAttribute {
+				MarkdownDescription:	"FCP devices reserved for the attachment",
+				ElementType:		types.StringType,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.List {
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (attachment *FeilongVolumeAttachment) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	attachment.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (attachment *FeilongVolumeAttachment) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FeilongVolumeAttachmentModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := attachment.Client
+	userid := data.UserId.ValueString()
+
+	// Compute values passed to Feilong API but not part of the data model
+	targetWWPNs := []string {}
+	resp.Diagnostics.Append(data.TargetWWPNs.ElementsAs(ctx, &targetWWPNs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	fcpTemplateId := data.FCPTemplateId.ValueString()
+	storageProvider := data.StorageProvider.ValueString()
+	multipath := data.Multipath.ValueBool()
+	doRollback := data.DoRollback.ValueBool()
+
+	// Reserve FCP devices for the guest
+	reserve := true
+	connectorParams := feilong.GetVolumeConnectorParams {
+		Reserve:	&reserve,
+		FCPTemplateId:	fcpTemplateId,
+		StorageProvider: storageProvider,
+	}
+	connector, err := client.GetVolumeConnector(userid, &connectorParams)
+	if err != nil {
+		resp.Diagnostics.AddError("FCP Reservation Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	fcpDevices := connector.Output.FCP
+
+	// Attach the volume
+	attachParams := feilong.AttachGuestVolumeParams {
+		AssignerId:	userid,
+		FCPList:	fcpDevices,
+		FCPTemplateId:	fcpTemplateId,
+		TargetWWPN:	targetWWPNs,
+		TargetLUN:	data.LUN.ValueString(),
+		OSVersion:	data.OSVersion.ValueString(),
+		Multipath:	&multipath,
+		MountPoint:	data.MountPoint.ValueString(),
+		DoRollback:	&doRollback,
+	}
+	err = client.AttachGuestVolume(&attachParams)
+	if err != nil {
+		resp.Diagnostics.AddError("Volume Attachment Error", fmt.Sprintf("Got error: %s", err))
+		if doRollback {
+			// do not leave FCP devices reserved for nothing
+			err = releaseFCPDevices(ctx, client, userid, fcpTemplateId, storageProvider)
+			if err != nil {
+				resp.Diagnostics.AddError("FCP Release Error", fmt.Sprintf("Got error: %s", err))
+			}
+		}
+		return
+	}
+
+	fcpDevicesValue, diags := types.ListValueFrom(ctx, types.StringType, fcpDevices)
+	resp.Diagnostics.Append(diags...)
+	data.FCPDevices = fcpDevicesValue
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Created a Feilong volume attachment resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (attachment *FeilongVolumeAttachment) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FeilongVolumeAttachmentModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// CAVEATS:
+	//  - Feilong does not report the volumes attached to a guest,
+	//    so the attachment cannot be verified after its creation
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (attachment *FeilongVolumeAttachment) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data FeilongVolumeAttachmentModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// All attributes of the attachment itself require a replacement,
+	// so only the resource name and the rollback flag can change here
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Updated Feilong volume attachment resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (attachment *FeilongVolumeAttachment) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FeilongVolumeAttachmentModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := attachment.Client
+	userid := data.UserId.ValueString()
+
+	// Compute values passed to Feilong API but not part of the data model
+	targetWWPNs := []string {}
+	resp.Diagnostics.Append(data.TargetWWPNs.ElementsAs(ctx, &targetWWPNs, false)...)
+	fcpDevices := []string {}
+	resp.Diagnostics.Append(data.FCPDevices.ElementsAs(ctx, &fcpDevices, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	fcpTemplateId := data.FCPTemplateId.ValueString()
+	multipath := data.Multipath.ValueBool()
+	doRollback := data.DoRollback.ValueBool()
+
+	// Detach the volume
+	detachParams := feilong.DetachGuestVolumeParams {
+		AssignerId:	userid,
+		FCPList:	fcpDevices,
+		FCPTemplateId:	fcpTemplateId,
+		TargetWWPN:	targetWWPNs,
+		TargetLUN:	data.LUN.ValueString(),
+		OSVersion:	data.OSVersion.ValueString(),
+		Multipath:	&multipath,
+		MountPoint:	data.MountPoint.ValueString(),
+		DoRollback:	&doRollback,
+	}
+	err := client.DetachGuestVolume(&detachParams)
+	if err != nil {
+		resp.Diagnostics.AddError("Volume Detachment Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Release the FCP devices
+	err = releaseFCPDevices(ctx, client, userid, fcpTemplateId, data.StorageProvider.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("FCP Release Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Deleted the Feilong volume attachment resource")
+}
+
+// For internal use
+
+func releaseFCPDevices(ctx context.Context, client *feilong.Client, userid string, fcpTemplateId string, storageProvider string) error {
+	// The FCP devices are shared by all volumes of the guest attached through the template,
+	// so keep them while other volumes still use them
+	result, err := client.GetFCPTemplatesDetails([]string { fcpTemplateId }, true, false, false)
+	if err != nil {
+		return err
+	}
+	for _, template := range result.Output.FCPTemplates {
+		for _, device := range guestFCPDevices(&template, userid) {
+			if device.Connections > 0 {
+				tflog.Info(ctx, "FCP device " + device.FCP + " still used by guest " + userid + ", not releasing FCP devices")
+				return nil
+			}
+		}
+	}
+
+	reserve := false
+	connectorParams := feilong.GetVolumeConnectorParams {
+		Reserve:	&reserve,
+		FCPTemplateId:	fcpTemplateId,
+		StorageProvider: storageProvider,
+	}
+	_, err = client.GetVolumeConnector(userid, &connectorParams)
+	return err
+}
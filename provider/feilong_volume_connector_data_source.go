@@ -0,0 +1,166 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FeilongVolumeConnectorDataSource{}
+
+func NewFeilongVolumeConnectorDataSource() datasource.DataSource {
+	return &FeilongVolumeConnectorDataSource{}
+}
+
+// FeilongVolumeConnectorDataSource defines the data source implementation.
+type FeilongVolumeConnectorDataSource struct {
+	Client *feilong.Client
+}
+
+// FeilongVolumeConnectorDataSourceModel describes the data source data model.
+type FeilongVolumeConnectorDataSourceModel struct {
+	UserId		types.String	`tfsdk:"userid"`
+	FCPTemplateId	types.String	`tfsdk:"fcp_template_id"`
+	StorageProvider	types.String	`tfsdk:"storage_provider"`
+	Reserve		types.Bool	`tfsdk:"reserve"`
+	FCPDevices	[]types.String	`tfsdk:"fcp_devices"`
+	WWPNs		[]types.String	`tfsdk:"wwpns"`
+	Host		types.String	`tfsdk:"host"`
+}
+
+func (connector *FeilongVolumeConnectorDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_volume_connector"
+}
+
+func (connector *FeilongVolumeConnectorDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong volume connector data source",
+
+		Attributes: map[string]schema.Attribute {
+			"userid": schema.StringAttribute {
+				MarkdownDescription:	"System name for z/VM of the guest",
+				Required:		true,
+			},
+			"fcp_template_id": schema.StringAttribute {
+				MarkdownDescription:	"Identifier of the FCP template",
+				Optional:		true,
+			},
+			"storage_provider": schema.StringAttribute {
+				MarkdownDescription:	"Storage provider, to use its default FCP template",
+				Optional:		true,
+			},
+			"reserve": schema.BoolAttribute {
+				MarkdownDescription:	"Whether to reserve the FCP devices for the guest (default false: only report the FCP devices already reserved)",
+				Optional:		true,
+			},
+			"fcp_devices": schema.ListAttribute {
+				MarkdownDescription:	"FCP devices of the guest",
+				ElementType:		types.StringType,
+				Computed:		true,
+			},
+			"wwpns": schema.ListAttribute {
+				MarkdownDescription:	"World wide port names of the FCP devices",
+				ElementType:		types.StringType,
+				Computed:		true,
+			},
+			"host": schema.StringAttribute {
+				MarkdownDescription:	"Name of the z/VM host",
+				Computed:		true,
+			},
+		},
+	}
+}
+
+func (connector *FeilongVolumeConnectorDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	connector.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (connector *FeilongVolumeConnectorDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeilongVolumeConnectorDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := connector.Client
+	userid := strings.ToUpper(data.UserId.ValueString())
+	fcpTemplateId := data.FCPTemplateId.ValueString()
+	storageProvider := data.StorageProvider.ValueString()
+
+	data.FCPDevices = []types.String {}
+	data.WWPNs = []types.String {}
+	if data.Reserve.ValueBool() {
+		// Obtain the connector, reserving the FCP devices as requested
+		reserve := true
+		connectorParams := feilong.GetVolumeConnectorParams {
+			Reserve:	&reserve,
+			FCPTemplateId:	fcpTemplateId,
+			StorageProvider: storageProvider,
+		}
+		result, err := client.GetVolumeConnector(userid, &connectorParams)
+		if err != nil {
+			resp.Diagnostics.AddError("Volume Connector Querying Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+		for _, fcp := range result.Output.FCP {
+			data.FCPDevices = append(data.FCPDevices, types.StringValue(fcp))
+		}
+		for _, wwpn := range result.Output.WWPNs {
+			data.WWPNs = append(data.WWPNs, types.StringValue(wwpn))
+		}
+		data.Host = types.StringValue(result.Output.Host)
+	} else {
+		// Obtaining the connector without reserving would release the FCP devices,
+		// so read the usage of the FCP devices in the templates instead
+		var templateIds []string
+		if fcpTemplateId != "" {
+			templateIds = []string { fcpTemplateId }
+		}
+		result, err := client.GetFCPTemplatesDetails(templateIds, true, false, false)
+		if err != nil {
+			resp.Diagnostics.AddError("FCP Templates Querying Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+		data.Host = types.StringNull()
+		for _, template := range result.Output.FCPTemplates {
+			if fcpTemplateId == "" && storageProvider != "" && !slices.Contains(template.StorageProviders, storageProvider) {
+				continue
+			}
+			for _, device := range guestFCPDevices(&template, userid) {
+				data.FCPDevices = append(data.FCPDevices, types.StringValue(device.FCP))
+				data.WWPNs = append(data.WWPNs, types.StringValue(device.WWPN))
+			}
+			if template.HypervisorHostname != "" {
+				data.Host = types.StringValue(template.HypervisorHostname)
+			}
+		}
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read a Feilong volume connector data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -11,11 +11,17 @@ import (
 	"fmt"
 	"strings"
 	"strconv"
+	"sort"
 	"golang.org/x/exp/maps"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
@@ -69,50 +75,103 @@ func (guest *FeilongVSwitch) Schema(ctx context.Context, req resource.SchemaRequ
 				MarkdownDescription:	"Virtual switch name for z/VM",
 				Optional:		true,
 				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"real_device": schema.StringAttribute {
 				MarkdownDescription:	"Real device number",
 				Optional:		true,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"controller": schema.StringAttribute {
 				MarkdownDescription:	"Controller",
 				Optional:		true,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"connection_type": schema.StringAttribute {
 				MarkdownDescription:	"Connection type (CONNECT, DISCONNECT, or NOUPLINK)",
 				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplaceIf(unlessNullInStateString, requiresReplaceUnlessNullInStateDesc, requiresReplaceUnlessNullInStateDesc),
+				},
 			},
 			"network_type": schema.StringAttribute {
 				MarkdownDescription:	"Network type (IP or ETHERNET)",
 				Optional:		true,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"router": schema.StringAttribute {
 				MarkdownDescription:	"Router role (NONROUTER or PRIROUTER)",
 				Optional:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplaceIf(unlessNullInStateString, requiresReplaceUnlessNullInStateDesc, requiresReplaceUnlessNullInStateDesc),
+				},
 			},
 			"vlan_id": schema.Int64Attribute {
 				MarkdownDescription:	"VLAN identifier",
 				Optional:		true,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.Int64 {
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
 			},
 			"port_type": schema.StringAttribute {
 				MarkdownDescription:	"Port type (ACCESS or TRUNK)",
 				Optional:		true,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"gvrp": schema.StringAttribute {
 				MarkdownDescription:	"Whether to use GVRP protocol (GVRP or NOGVRP)",
 				Optional:		true,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"queue_mem": schema.Int64Attribute {
 				MarkdownDescription:	"QDIO buffer size in megabytes",
 				Optional:		true,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.Int64 {
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
 			},
 			"native_vlan_id": schema.Int64Attribute {
 				MarkdownDescription:	"Native VLAN identifier",
 				Optional:		true,
+				Computed:		true,
+				PlanModifiers:		[]planmodifier.Int64 {
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
 			},
 			"persist": schema.BoolAttribute {
 				MarkdownDescription:	"Whether virtual switch is permanent",
 				Optional:		true,
+				PlanModifiers:		[]planmodifier.Bool {
+					boolplanmodifier.RequiresReplaceIf(unlessNullInStateBool, requiresReplaceUnlessNullInStateDesc, requiresReplaceUnlessNullInStateDesc),
+				},
 			},
 		},
 	}
@@ -147,10 +206,36 @@ func (guest *FeilongVSwitch) Create(ctx context.Context, req resource.CreateRequ
 		data.VSwitch = types.StringValue(vswitch)
 	}
 
+	// Undeclared values will be read back after creation
+	if data.RealDevice.IsUnknown() {
+		data.RealDevice = types.StringNull()
+	}
+	if data.Controller.IsUnknown() {
+		data.Controller = types.StringNull()
+	}
+	if data.NetworkType.IsUnknown() {
+		data.NetworkType = types.StringNull()
+	}
+	if data.VLANId.IsUnknown() {
+		data.VLANId = types.Int64Null()
+	}
+	if data.PortType.IsUnknown() {
+		data.PortType = types.StringNull()
+	}
+	if data.GVRP.IsUnknown() {
+		data.GVRP = types.StringNull()
+	}
+	if data.QueueMem.IsUnknown() {
+		data.QueueMem = types.Int64Null()
+	}
+	if data.NativeVLANId.IsUnknown() {
+		data.NativeVLANId = types.Int64Null()
+	}
+
 	// Create the virtual switch
 	client := guest.Client
 	persist := data.Persist.ValueBool()
-	createParams := feilong.CreateVSwitchParams { Name: data.Name.ValueString() }
+	createParams := feilong.CreateVSwitchParams { Name: vswitch }
         if !data.RealDevice.IsNull() {
                 createParams.RealDev = data.RealDevice.ValueString()
         }
@@ -191,6 +276,17 @@ func (guest *FeilongVSwitch) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	// Get computed values
+	found, diags := readVSwitch(ctx, client, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError("VSwitch Not Found Error", fmt.Sprintf("Virtual switch \"%s\" not listed after creation", vswitch))
+		return
+	}
+
 	// Write logs using the tflog package
 	tflog.Trace(ctx, "created a Feilong virtual switch resource")
 
@@ -207,29 +303,131 @@ func (guest *FeilongVSwitch) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	// Obtain info about this vswitch
+	found, diags := readVSwitch(ctx, guest.Client, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		// the vswitch was deleted outside of terraform
+		tflog.Info(ctx, "Virtual switch " + data.VSwitch.ValueString() + " not found, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// CAVEATS:
+	//  - the connection type cannot be determined after the deployment
+	//  - the router cannot be determined after the deployment
+	//  - the persist flag cannot be determined after the deployment
+	//  - if undeclared at creation, they get adopted from the configuration
+	//    without replacing the vswitch
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read characteristics of Feilong virtual switch resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (guest *FeilongVSwitch) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data FeilongVSwitchModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Feilong cannot modify an existing virtual switch: all attributes
+	// of the vswitch itself require a replacement, so only the resource
+	// name can change here
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Updated Feilong virtual switch resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (guest *FeilongVSwitch) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FeilongVSwitchModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	client := guest.Client
 	vswitch := data.VSwitch.ValueString()
 
-	// Obtain info about this vswitch
-	vswitchDetails, err := client.GetVSwitchDetails(vswitch)
+	err := client.DeleteVSwitch(vswitch)
 	if err != nil {
-		resp.Diagnostics.AddError("VSwitch Querying Error", fmt.Sprintf("Got error: %s", err))
+		resp.Diagnostics.AddError("Deletion Error", fmt.Sprintf("Got error: %s", err))
 		return
 	}
+}
+
+func (guest *FeilongVSwitch) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The import identifier is the z/VM vswitch name; Read does the rest
+	vswitch := strings.ToUpper(req.ID)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vswitch"), vswitch)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), strings.ToLower(vswitch))...)
+}
+
+// For internal use
+
+func readVSwitch(ctx context.Context, client *feilong.Client, data *FeilongVSwitchModel) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	// Check that this vswitch exists
+	vswitch := data.VSwitch.ValueString()
+	found, err := vswitchExists(client, vswitch)
+	if err != nil {
+		diags.AddError("VSwitch Listing Error", fmt.Sprintf("Got error: %s", err))
+		return false, diags
+	}
+	if !found {
+		return false, diags
+	}
+
+	// Obtain details
+	vswitchDetails, err := client.GetVSwitchDetails(vswitch)
+	if err != nil {
+		diags.AddError("VSwitch Querying Error", fmt.Sprintf("Got error: %s", err))
+		return false, diags
+	}
 
 	// Read real device
 	devices := maps.Keys(vswitchDetails.Output.RealDevices)
-	if len(devices) != 1 {
-		resp.Diagnostics.AddError("VSwitch Querying Error", fmt.Sprintf("Unexpected number of real devices %d for vswitch %s", len(devices), vswitch))
-		return
+	sort.Strings(devices)
+	realDevice := ""
+	for _, device := range devices {
+		if strings.EqualFold(device, data.RealDevice.ValueString()) {
+			realDevice = device
+			break
+		}
+	}
+	if realDevice != "" {
+		tflog.Info(ctx, "Not replacing declared real device " + data.RealDevice.ValueString() + " with same device " + realDevice)
+	} else if len(devices) > 0 {
+		realDevice = devices[0]
+		data.RealDevice = types.StringValue(realDevice)
+	} else {
+		// vswitch without uplink
+		data.RealDevice = types.StringNull()
 	}
-	realDevice := devices[0]
-	data.RealDevice = types.StringValue(realDevice)
 
 	// Read controller
-	controller := vswitchDetails.Output.RealDevices[realDevice].Controller
+	controller := "NONE"
+	if realDevice != "" {
+		controller = vswitchDetails.Output.RealDevices[realDevice].Controller
+	}
 	if data.Controller.IsNull() && controller == "NONE" {
 		tflog.Info(ctx, "Not replacing undeclared controller with default value NONE")
+	} else if data.Controller.ValueString() == "*" && controller != "NONE" {
+		tflog.Info(ctx, "Not replacing declared controller * with actual controller " + controller)
 	} else {
 		data.Controller = types.StringValue(controller)
 	}
@@ -243,16 +441,24 @@ func (guest *FeilongVSwitch) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	// Read VLAN id
-	vlanId, err := strconv.Atoi(vswitchDetails.Output.VLANId)
-	if err != nil {
-		resp.Diagnostics.AddError("VLAN Id Conversion Error: %s", fmt.Sprintf("Got error: %s", err))
-		return
+	if vswitchDetails.Output.VLANAwareness == "UNAWARE" {
+		data.VLANId = types.Int64Null()
+	} else {
+		vlanId, err := strconv.Atoi(vswitchDetails.Output.VLANId)
+		if err != nil {
+			diags.AddError("VLAN Id Conversion Error", fmt.Sprintf("Got error: %s", err))
+			return false, diags
+		}
+		data.VLANId = types.Int64Value(int64(vlanId))
 	}
-	data.VLANId = types.Int64Value(int64(vlanId))
 
 	// Read port type
 	portType := vswitchDetails.Output.PortType
-	data.PortType = types.StringValue(portType)
+	if data.PortType.IsNull() && (portType == "" || portType == "NONE") {
+		tflog.Info(ctx, "Not replacing undeclared port type with empty value")
+	} else {
+		data.PortType = types.StringValue(portType)
+	}
 
 	// Read GVRP
 	gvrp := vswitchDetails.Output.GVRPEnabledAttribute
@@ -265,8 +471,8 @@ func (guest *FeilongVSwitch) Read(ctx context.Context, req resource.ReadRequest,
 	// Read queue memory
 	queueMem, err := strconv.Atoi(vswitchDetails.Output.QueueMemoryLimit)
 	if err != nil {
-		resp.Diagnostics.AddError("Queue Memory Conversion Error: %s", fmt.Sprintf("Got error: %s", err))
-		return
+		diags.AddError("Queue Memory Conversion Error", fmt.Sprintf("Got error: %s", err))
+		return false, diags
 	}
 	if data.QueueMem.IsNull() && queueMem == 8 {
 		tflog.Info(ctx, "Not replacing undeclared queue memory with default value 8")
@@ -275,69 +481,46 @@ func (guest *FeilongVSwitch) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	// Read native VLAN id
-	nativeVlanId, err := strconv.Atoi(vswitchDetails.Output.NativeVLANId)
-	if err != nil {
-		resp.Diagnostics.AddError("Native VLAN Id Conversion Error: %s", fmt.Sprintf("Got error: %s", err))
-		return
-	}
-	if data.NativeVLANId.IsNull() && nativeVlanId == 1 {
-		tflog.Info(ctx, "Not replacing undeclared native VLAN id with default value 1")
+	if vswitchDetails.Output.VLANAwareness == "UNAWARE" {
+		data.NativeVLANId = types.Int64Null()
 	} else {
-		data.NativeVLANId = types.Int64Value(int64(nativeVlanId))
+		nativeVlanId, err := strconv.Atoi(vswitchDetails.Output.NativeVLANId)
+		if err != nil {
+			diags.AddError("Native VLAN Id Conversion Error", fmt.Sprintf("Got error: %s", err))
+			return false, diags
+		}
+		if data.NativeVLANId.IsNull() && nativeVlanId == 1 {
+			tflog.Info(ctx, "Not replacing undeclared native VLAN id with default value 1")
+		} else {
+			data.NativeVLANId = types.Int64Value(int64(nativeVlanId))
+		}
 	}
 
-	// CAVEATS:
-	//  - the connection type cannot be determined after the deployment
-	//  - the router cannot be determined after the deployment
-	//  - the persist flag cannot be determined after the deployment
-
-	// Write logs using the tflog package
-	tflog.Trace(ctx, "Read characteristics of Feilong virtual switch resource")
-
-	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	return true, diags
 }
 
-func (guest *FeilongVSwitch) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data FeilongVSwitchModel
-
-	// Read Terraform plan data into the model
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
+// The connection type, the router and the persist flag cannot be read back
+// from z/VM, so they are null in the state after an import: adopt the declared values
+// instead of replacing the vswitch
+const requiresReplaceUnlessNullInStateDesc string = "Changing this value requires a replacement, unless there is no previous value in the state"
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := r.client.Do(httpReq)
-	// if err != nil {
-	//	resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update example, got error: %s", err))
-	//	return
-	// }
-
-	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+func unlessNullInStateString(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.StateValue.IsNull()
 }
 
-func (guest *FeilongVSwitch) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data FeilongVSwitchModel
-
-	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	client := guest.Client
-	name := data.Name.ValueString()
+func unlessNullInStateBool(ctx context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.StateValue.IsNull()
+}
 
-	err := client.DeleteVSwitch(name)
+func vswitchExists(client *feilong.Client, vswitch string) (bool, error) {
+	result, err := client.ListVSwitches()
 	if err != nil {
-		resp.Diagnostics.AddError("Deletion Error", fmt.Sprintf("Got error: %s", err))
-		return
+		return false, err
 	}
-}
-
-func (guest *FeilongVSwitch) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	for _, name := range result.Output {
+		if strings.EqualFold(name, vswitch) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
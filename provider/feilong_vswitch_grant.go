@@ -0,0 +1,300 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &FeilongVSwitchGrant{}
+
+func NewFeilongVSwitchGrant() resource.Resource {
+	return &FeilongVSwitchGrant{}
+}
+
+// FeilongVSwitchGrant defines the resource implementation.
+type FeilongVSwitchGrant struct {
+	Client *feilong.Client
+}
+
+// FeilongVSwitchGrantModel describes the resource data model.
+type FeilongVSwitchGrantModel struct {
+	Name		types.String	`tfsdk:"name"`
+	VSwitch		types.String	`tfsdk:"vswitch"`
+	UserId		types.String	`tfsdk:"userid"`
+	VLANId		types.Int64	`tfsdk:"vlan_id"`
+}
+
+func (grant *FeilongVSwitchGrant) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vswitch_grant"
+}
+
+func (grant *FeilongVSwitchGrant) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong virtual switch authorization resource",
+
+		Attributes: map[string]schema.Attribute {
+			"name": schema.StringAttribute {
+				MarkdownDescription:	"Arbitrary name for the resource",
+				Required:		true,
+			},
+			"vswitch": schema.StringAttribute {
+				MarkdownDescription:	"Virtual switch name for z/VM",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"userid": schema.StringAttribute {
+				MarkdownDescription:	"System name for z/VM of the guest granted access to the virtual switch",
+				Required:		true,
+				PlanModifiers:		[]planmodifier.String {
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vlan_id": schema.Int64Attribute {
+				MarkdownDescription:	"VLAN identifier of the guest on the virtual switch",
+				Optional:		true,
+				PlanModifiers:		[]planmodifier.Int64 {
+					int64planmodifier.RequiresReplaceIf(ifVLANIdUnset, requiresReplaceIfVLANIdUnsetDesc, requiresReplaceIfVLANIdUnsetDesc),
+				},
+			},
+		},
+	}
+}
+
+func (grant *FeilongVSwitchGrant) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	grant.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (grant *FeilongVSwitchGrant) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FeilongVSwitchGrantModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := grant.Client
+	vswitch := data.VSwitch.ValueString()
+	userid := data.UserId.ValueString()
+
+	// Grant access to the virtual switch
+	grantParams := feilong.GrantUserToVSwitchParams {
+		GrantUserId:	userid,
+	}
+	err := client.GrantUserToVSwitch(vswitch, &grantParams)
+	if err != nil {
+		resp.Diagnostics.AddError("Creation Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Set the VLAN of the guest
+	if !data.VLANId.IsNull() {
+		err = setUserVLANId(client, vswitch, userid, data.VLANId.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("VLAN Id Setting Error", fmt.Sprintf("Got error: %s", err))
+
+			// Do not leave behind an authorization that is not in the state
+			revokeParams := feilong.RevokeUserFromVSwitchParams {
+				RevokeUserId:	userid,
+			}
+			err = client.RevokeUserFromVSwitch(vswitch, &revokeParams)
+			if err != nil {
+				resp.Diagnostics.AddError("Authorization Cleanup Error", fmt.Sprintf("Got error: %s, the authorization might need to be revoked by hand", err))
+			}
+			return
+		}
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Created a Feilong virtual switch authorization resource")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (grant *FeilongVSwitchGrant) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FeilongVSwitchGrantModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := grant.Client
+	vswitch := data.VSwitch.ValueString()
+	userid := data.UserId.ValueString()
+
+	// Check that this vswitch exists
+	found, err := vswitchExists(client, vswitch)
+	if err != nil {
+		resp.Diagnostics.AddError("VSwitch Listing Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	if !found {
+		// the vswitch was deleted outside of terraform, and the authorization with it
+		tflog.Info(ctx, "Virtual switch " + vswitch + " not found, removing authorization of guest " + userid + " from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Obtain info about this vswitch
+	vswitchDetails, err := client.GetVSwitchDetails(vswitch)
+	if err != nil {
+		resp.Diagnostics.AddError("VSwitch Querying Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Find the authorized user
+	var authorizedUser *feilong.VSwitchAuthorizedUser
+	for user, details := range vswitchDetails.Output.AuthorizedUsers {
+		if strings.EqualFold(user, userid) {
+			authorizedUser = &details
+			break
+		}
+	}
+	if authorizedUser == nil {
+		// the authorization was revoked outside of terraform
+		tflog.Info(ctx, "Guest " + userid + " not authorized on virtual switch " + vswitch + ", removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Read VLAN id
+	if data.VLANId.IsNull() {
+		tflog.Info(ctx, "Not replacing undeclared VLAN id")
+	} else {
+		declaredVLANId := strconv.FormatInt(data.VLANId.ValueInt64(), 10)
+		found := false
+		for _, vlanId := range authorizedUser.VLANIds {
+			if vlanId == declaredVLANId {
+				found = true
+				break
+			}
+		}
+		if !found {
+			if len(authorizedUser.VLANIds) == 0 {
+				data.VLANId = types.Int64Null()
+			} else {
+				vlanId, err := strconv.Atoi(authorizedUser.VLANIds[0])
+				if err != nil {
+					resp.Diagnostics.AddError("VLAN Id Conversion Error", fmt.Sprintf("Got error: %s", err))
+					return
+				}
+				data.VLANId = types.Int64Value(int64(vlanId))
+			}
+		}
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read characteristics of Feilong virtual switch authorization resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (grant *FeilongVSwitchGrant) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data FeilongVSwitchGrantModel
+	var state FeilongVSwitchGrantModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Also read current state, for comparaison
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := grant.Client
+	vswitch := data.VSwitch.ValueString()
+	userid := data.UserId.ValueString()
+
+	// Address VLAN id changes (unsetting it requires a replacement)
+	if !data.VLANId.Equal(state.VLANId) {
+		err := setUserVLANId(client, vswitch, userid, data.VLANId.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("VLAN Id Setting Error", fmt.Sprintf("Got error: %s", err))
+			return
+		}
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Updated Feilong virtual switch authorization resource")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (grant *FeilongVSwitchGrant) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FeilongVSwitchGrantModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := grant.Client
+
+	// Revoke access to the virtual switch
+	revokeParams := feilong.RevokeUserFromVSwitchParams {
+		RevokeUserId:	data.UserId.ValueString(),
+	}
+	err := client.RevokeUserFromVSwitch(data.VSwitch.ValueString(), &revokeParams)
+	if err != nil {
+		resp.Diagnostics.AddError("Deletion Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Deleted the Feilong virtual switch authorization resource")
+}
+
+// For internal use
+
+// A VLAN id cannot be unset, the authorization must be revoked and granted again
+const requiresReplaceIfVLANIdUnsetDesc string = "Unsetting this value requires a replacement"
+
+func ifVLANIdUnset(ctx context.Context, req planmodifier.Int64Request, resp *int64planmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = req.ConfigValue.IsNull() && !req.StateValue.IsNull()
+}
+
+func setUserVLANId(client *feilong.Client, vswitch string, userid string, vlanId int64) error {
+	params := feilong.SetUserVLANIdToVSwitchParams {
+		UserVLANId:	feilong.UserVLANId {
+			UserId:		userid,
+			VLANId:		int(vlanId),
+		},
+	}
+	return client.SetUserVLANIdToVSwitch(vswitch, &params)
+}
@@ -0,0 +1,296 @@
+/**
+  Copyright Contributors to the Feilong Project.
+
+  SPDX-License-Identifier: Apache-2.0
+**/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"golang.org/x/exp/maps"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/Bischoff/feilong-client-go"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FeilongVSwitchesDataSource{}
+
+func NewFeilongVSwitchesDataSource() datasource.DataSource {
+	return &FeilongVSwitchesDataSource{}
+}
+
+// FeilongVSwitchesDataSource defines the data source implementation.
+type FeilongVSwitchesDataSource struct {
+	Client *feilong.Client
+}
+
+// FeilongVSwitchesDataSourceModel describes the data source data model.
+type FeilongVSwitchesDataSourceModel struct {
+	Details		types.Bool	`tfsdk:"details"`
+	VSwitches	[]FeilongVSwitchesDataSourceVSwitch `tfsdk:"vswitches"`
+}
+
+// FeilongVSwitchesDataSourceVSwitch describes one virtual switch of the data source.
+type FeilongVSwitchesDataSourceVSwitch struct {
+	VSwitch		types.String	`tfsdk:"vswitch"`
+	Type		types.String	`tfsdk:"type"`
+	Status		types.String	`tfsdk:"status"`
+	NetworkType	types.String	`tfsdk:"network_type"`
+	VLANAwareness	types.String	`tfsdk:"vlan_awareness"`
+	VLANId		types.Int64	`tfsdk:"vlan_id"`
+	NativeVLANId	types.Int64	`tfsdk:"native_vlan_id"`
+	PortType	types.String	`tfsdk:"port_type"`
+	GVRP		types.String	`tfsdk:"gvrp"`
+	QueueMem	types.Int64	`tfsdk:"queue_mem"`
+	RealDevices	[]FeilongVSwitchesDataSourceRealDevice `tfsdk:"real_devices"`
+	AuthorizedUsers	[]FeilongVSwitchesDataSourceAuthorizedUser `tfsdk:"authorized_users"`
+}
+
+// FeilongVSwitchesDataSourceRealDevice describes one real device of a virtual switch.
+type FeilongVSwitchesDataSourceRealDevice struct {
+	RealDevice	types.String	`tfsdk:"real_device"`
+	Controller	types.String	`tfsdk:"controller"`
+	PortName	types.String	`tfsdk:"port_name"`
+	Status		types.String	`tfsdk:"status"`
+}
+
+// FeilongVSwitchesDataSourceAuthorizedUser describes one guest authorized on a virtual switch.
+type FeilongVSwitchesDataSourceAuthorizedUser struct {
+	UserId		types.String	`tfsdk:"userid"`
+	VLANIds		[]types.String	`tfsdk:"vlan_ids"`
+	PromiscuousMode	types.String	`tfsdk:"promiscuous_mode"`
+}
+
+func (vswitches *FeilongVSwitchesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vswitches"
+}
+
+func (vswitches *FeilongVSwitchesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema {
+		MarkdownDescription: "Feilong virtual switches data source",
+
+		Attributes: map[string]schema.Attribute {
+			"details": schema.BoolAttribute {
+				MarkdownDescription:	"Whether to look up the details of each virtual switch",
+				Optional:		true,
+			},
+			"vswitches": schema.ListNestedAttribute {
+				MarkdownDescription:	"Virtual switches, sorted by name",
+				Computed:		true,
+				NestedObject:		schema.NestedAttributeObject {
+					Attributes: map[string]schema.Attribute {
+						"vswitch": schema.StringAttribute {
+							MarkdownDescription:	"Virtual switch name for z/VM",
+							Computed:		true,
+						},
+						"type": schema.StringAttribute {
+							MarkdownDescription:	"Type of the virtual switch",
+							Computed:		true,
+						},
+						"status": schema.StringAttribute {
+							MarkdownDescription:	"Status of the virtual switch",
+							Computed:		true,
+						},
+						"network_type": schema.StringAttribute {
+							MarkdownDescription:	"Network type (IP or ETHERNET)",
+							Computed:		true,
+						},
+						"vlan_awareness": schema.StringAttribute {
+							MarkdownDescription:	"Whether the virtual switch is VLAN aware (AWARE or UNAWARE)",
+							Computed:		true,
+						},
+						"vlan_id": schema.Int64Attribute {
+							MarkdownDescription:	"VLAN identifier",
+							Computed:		true,
+						},
+						"native_vlan_id": schema.Int64Attribute {
+							MarkdownDescription:	"Native VLAN identifier",
+							Computed:		true,
+						},
+						"port_type": schema.StringAttribute {
+							MarkdownDescription:	"Port type (ACCESS or TRUNK)",
+							Computed:		true,
+						},
+						"gvrp": schema.StringAttribute {
+							MarkdownDescription:	"Whether GVRP protocol is used (GVRP or NOGVRP)",
+							Computed:		true,
+						},
+						"queue_mem": schema.Int64Attribute {
+							MarkdownDescription:	"QDIO buffer size in megabytes",
+							Computed:		true,
+						},
+						"real_devices": schema.ListNestedAttribute {
+							MarkdownDescription:	"Real devices of the virtual switch",
+							Computed:		true,
+							NestedObject:		schema.NestedAttributeObject {
+								Attributes: map[string]schema.Attribute {
+									"real_device": schema.StringAttribute {
+										MarkdownDescription:	"Real device number",
+										Computed:		true,
+									},
+									"controller": schema.StringAttribute {
+										MarkdownDescription:	"Controller",
+										Computed:		true,
+									},
+									"port_name": schema.StringAttribute {
+										MarkdownDescription:	"Port name",
+										Computed:		true,
+									},
+									"status": schema.StringAttribute {
+										MarkdownDescription:	"Status of the device",
+										Computed:		true,
+									},
+								},
+							},
+						},
+						"authorized_users": schema.ListNestedAttribute {
+							MarkdownDescription:	"Guests authorized on the virtual switch",
+							Computed:		true,
+							NestedObject:		schema.NestedAttributeObject {
+								Attributes: map[string]schema.Attribute {
+									"userid": schema.StringAttribute {
+										MarkdownDescription:	"System name for z/VM of the guest",
+										Computed:		true,
+									},
+									"vlan_ids": schema.ListAttribute {
+										MarkdownDescription:	"VLAN identifiers of the guest",
+										ElementType:		types.StringType,
+										Computed:		true,
+									},
+									"promiscuous_mode": schema.StringAttribute {
+										MarkdownDescription:	"Promiscuous mode of the guest",
+										Computed:		true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (vswitches *FeilongVSwitchesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	vswitches.Client = &req.ProviderData.(*apiClient).Client
+}
+
+func (vswitches *FeilongVSwitchesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FeilongVSwitchesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Obtain the list of vswitches
+	client := vswitches.Client
+	result, err := client.ListVSwitches()
+	if err != nil {
+		resp.Diagnostics.AddError("VSwitch Listing Error", fmt.Sprintf("Got error: %s", err))
+		return
+	}
+	names := result.Output
+	sort.Strings(names)
+
+	// Register the vswitches
+	data.VSwitches = []FeilongVSwitchesDataSourceVSwitch {}
+	for _, name := range names {
+		vswitch := FeilongVSwitchesDataSourceVSwitch {
+			VSwitch:	types.StringValue(name),
+			Type:		types.StringNull(),
+			Status:		types.StringNull(),
+			NetworkType:	types.StringNull(),
+			VLANAwareness:	types.StringNull(),
+			VLANId:		types.Int64Null(),
+			NativeVLANId:	types.Int64Null(),
+			PortType:	types.StringNull(),
+			GVRP:		types.StringNull(),
+			QueueMem:	types.Int64Null(),
+		}
+		if data.Details.ValueBool() {
+			details, err := client.GetVSwitchDetails(name)
+			if err != nil {
+				resp.Diagnostics.AddError("VSwitch Querying Error", fmt.Sprintf("Got error: %s", err))
+				return
+			}
+			setVSwitchDetails(&vswitch, &details.Output)
+		}
+		data.VSwitches = append(data.VSwitches, vswitch)
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "Read a Feilong virtual switches data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// For internal use
+
+func setVSwitchDetails(vswitch *FeilongVSwitchesDataSourceVSwitch, details *feilong.GetVSwitchDetailsOutput) {
+	vswitch.Type = types.StringValue(details.SwitchType)
+	vswitch.Status = types.StringValue(details.SwitchStatus)
+	vswitch.NetworkType = types.StringValue(details.TransportType)
+	vswitch.VLANAwareness = types.StringValue(details.VLANAwareness)
+	vswitch.PortType = types.StringValue(details.PortType)
+	vswitch.GVRP = types.StringValue(details.GVRPEnabledAttribute)
+
+	// VLAN unaware switches do not have numeric VLAN ids
+	vlanId, err := strconv.Atoi(details.VLANId)
+	if err == nil {
+		vswitch.VLANId = types.Int64Value(int64(vlanId))
+	}
+	nativeVlanId, err := strconv.Atoi(details.NativeVLANId)
+	if err == nil {
+		vswitch.NativeVLANId = types.Int64Value(int64(nativeVlanId))
+	}
+	queueMem, err := strconv.Atoi(details.QueueMemoryLimit)
+	if err == nil {
+		vswitch.QueueMem = types.Int64Value(int64(queueMem))
+	}
+
+	devices := maps.Keys(details.RealDevices)
+	sort.Strings(devices)
+	vswitch.RealDevices = []FeilongVSwitchesDataSourceRealDevice {}
+	for _, device := range devices {
+		realDevice := details.RealDevices[device]
+		vswitch.RealDevices = append(vswitch.RealDevices, FeilongVSwitchesDataSourceRealDevice {
+			RealDevice:	types.StringValue(device),
+			Controller:	types.StringValue(realDevice.Controller),
+			PortName:	types.StringValue(realDevice.PortName),
+			Status:		types.StringValue(realDevice.DevStatus),
+		})
+	}
+
+	users := maps.Keys(details.AuthorizedUsers)
+	sort.Strings(users)
+	vswitch.AuthorizedUsers = []FeilongVSwitchesDataSourceAuthorizedUser {}
+	for _, user := range users {
+		authorizedUser := details.AuthorizedUsers[user]
+		vlanIds := []types.String {}
+		for _, vlanId := range authorizedUser.VLANIds {
+			vlanIds = append(vlanIds, types.StringValue(vlanId))
+		}
+		vswitch.AuthorizedUsers = append(vswitch.AuthorizedUsers, FeilongVSwitchesDataSourceAuthorizedUser {
+			UserId:		types.StringValue(user),
+			VLANIds:	vlanIds,
+			PromiscuousMode: types.StringValue(authorizedUser.PromMode),
+		})
+	}
+}
@@ -7,8 +7,17 @@
 package provider
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
@@ -40,6 +49,15 @@ type FeilongProviderModel struct {
 	Connector	types.String	`tfsdk:"connector"`
 	AdminToken	types.String	`tfsdk:"admin_token"`
 	LocalUser	types.String	`tfsdk:"local_user"`
+	Profile		types.String	`tfsdk:"profile"`
+	CredentialsFile	types.String	`tfsdk:"credentials_file"`
+	CACertFile	types.String	`tfsdk:"ca_cert_file"`
+	CACertPEM	types.String	`tfsdk:"ca_cert_pem"`
+	ClientCert	types.String	`tfsdk:"client_cert"`
+	ClientKey	types.String	`tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool	`tfsdk:"insecure_skip_verify"`
+	CheckSMAPIHealth types.Bool	`tfsdk:"check_smapi_health"`
+	MaxSMAPIFailures types.Int64	`tfsdk:"max_smapi_failures"`
 }
 
 func (p *FeilongProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -51,15 +69,53 @@ func (p *FeilongProvider) Schema(ctx context.Context, req provider.SchemaRequest
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute {
 			"connector": schema.StringAttribute {
-				MarkdownDescription:	"URL of the z/VM connector",
-				Required:		true,
+				MarkdownDescription:	"URL of the z/VM connector (default: FEILONG_CONNECTOR environment variable, then credentials file)",
+				Optional:		true,
 			},
 			"admin_token": schema.StringAttribute {
-				MarkdownDescription:	"Shared secret to authenticate the client",
+				MarkdownDescription:	"Shared secret to authenticate the client (default: FEILONG_ADMIN_TOKEN environment variable, then credentials file)",
 				Optional:		true,
+				Sensitive:		true,
 			},
 			"local_user": schema.StringAttribute {
-				MarkdownDescription:	"Where parameter files are uploaded from",
+				MarkdownDescription:	"Where parameter files are uploaded from (default: FEILONG_LOCAL_USER environment variable, then credentials file)",
+				Optional:		true,
+			},
+			"profile": schema.StringAttribute {
+				MarkdownDescription:	"Profile to use in the credentials file (default: FEILONG_PROFILE environment variable, then \"default\")",
+				Optional:		true,
+			},
+			"credentials_file": schema.StringAttribute {
+				MarkdownDescription:	"Path to the credentials file (default: FEILONG_CREDENTIALS_FILE environment variable, then ~/.config/feilong/credentials)",
+				Optional:		true,
+			},
+			"ca_cert_file": schema.StringAttribute {
+				MarkdownDescription:	"Path to a PEM file with additional CA certificates to trust (default: FEILONG_CA_CERT_FILE environment variable, then credentials file)",
+				Optional:		true,
+			},
+			"ca_cert_pem": schema.StringAttribute {
+				MarkdownDescription:	"Additional CA certificates to trust, in PEM format (default: FEILONG_CA_CERT_PEM environment variable, then credentials file)",
+				Optional:		true,
+			},
+			"client_cert": schema.StringAttribute {
+				MarkdownDescription:	"Client certificate to present to the z/VM connector, as a path or in PEM format (default: FEILONG_CLIENT_CERT environment variable, then credentials file)",
+				Optional:		true,
+			},
+			"client_key": schema.StringAttribute {
+				MarkdownDescription:	"Private key of the client certificate, as a path or in PEM format (default: FEILONG_CLIENT_KEY environment variable, then credentials file)",
+				Optional:		true,
+				Sensitive:		true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute {
+				MarkdownDescription:	"Whether to skip the verification of the z/VM connector's certificate",
+				Optional:		true,
+			},
+			"check_smapi_health": schema.BoolAttribute {
+				MarkdownDescription:	"Whether to refuse to proceed when SMAPI is unhealthy",
+				Optional:		true,
+			},
+			"max_smapi_failures": schema.Int64Attribute {
+				MarkdownDescription:	"Refuse to proceed when more SMAPI calls failed in a row (implies check_smapi_health)",
 				Optional:		true,
 			},
 		},
@@ -75,12 +131,46 @@ func (p *FeilongProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
+	// Complete the configuration from the environment and from the credentials file
+	profile := configValue(config.Profile, "FEILONG_PROFILE", nil)
+	credentialsFile := configValue(config.CredentialsFile, "FEILONG_CREDENTIALS_FILE", nil)
+	credentials, err := readCredentials(credentialsFile, profile)
+	if err != nil {
+		resp.Diagnostics.AddError("Configuration Error", fmt.Sprintf("Unable to read credentials, got error: %s", err))
+		return
+	}
+	connector := configValue(config.Connector, "FEILONG_CONNECTOR", credentials)
+	adminToken := configValue(config.AdminToken, "FEILONG_ADMIN_TOKEN", credentials)
+	localUser := configValue(config.LocalUser, "FEILONG_LOCAL_USER", credentials)
+	if connector == "" {
+		resp.Diagnostics.AddError("Configuration Error", "Missing URL of the z/VM connector, set it with \"connector\", FEILONG_CONNECTOR or the credentials file")
+		return
+	}
+
 	// Create a new Feilong client using the configuration values
-	connector := config.Connector.ValueString()
 	client := feilong.NewClient(&connector, nil)
 
+	// If needed, set up TLS
+	tlsConfig, err := buildTLSConfig(
+		configValue(config.CACertFile, "FEILONG_CA_CERT_FILE", credentials),
+		configValue(config.CACertPEM, "FEILONG_CA_CERT_PEM", credentials),
+		configValue(config.ClientCert, "FEILONG_CLIENT_CERT", credentials),
+		configValue(config.ClientKey, "FEILONG_CLIENT_KEY", credentials),
+		config.InsecureSkipVerify.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError("TLS Error", fmt.Sprintf("Unable to set up TLS, got error: %s", err))
+		return
+	}
+	if tlsConfig != nil {
+		if tlsConfig.InsecureSkipVerify {
+			resp.Diagnostics.AddWarning("TLS Warning", "The certificate of the z/VM connector is not verified")
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client.HTTPClient.Transport = transport
+	}
+
 	// If needed, create an authentication token
-	adminToken := config.AdminToken.ValueString()
 	if adminToken != "" {
 		err := client.CreateToken(adminToken)
 		if err != nil {
@@ -100,8 +190,24 @@ func (p *FeilongProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
+	// If requested, check that SMAPI is healthy
+	if config.CheckSMAPIHealth.ValueBool() || !config.MaxSMAPIFailures.IsNull() {
+		health, err := getSMAPIHealth(ctx, client)
+		if err != nil {
+			resp.Diagnostics.AddError("SMAPI Error", fmt.Sprintf("Unable to get SMAPI health, got error: %s", err))
+			return
+		}
+		if health.Healthy != nil && !*health.Healthy {
+			resp.Diagnostics.AddError("SMAPI Error", fmt.Sprintf("SMAPI is unhealthy, last failure at %s", health.LastFail))
+			return
+		}
+		if !config.MaxSMAPIFailures.IsNull() && int64(health.ContinuousFail) > config.MaxSMAPIFailures.ValueInt64() {
+			resp.Diagnostics.AddError("SMAPI Error", fmt.Sprintf("SMAPI failed %d times in a row, more than %d", health.ContinuousFail, config.MaxSMAPIFailures.ValueInt64()))
+			return
+		}
+	}
+
 	// Make the Feilong client available during DataSource and Resource type Configure methods.
-	localUser := config.LocalUser.ValueString()
 	c := apiClient {
 		Client: *client,
 		LocalUser: localUser,
@@ -111,17 +217,35 @@ func (p *FeilongProvider) Configure(ctx context.Context, req provider.ConfigureR
 }
 
 func (p *FeilongProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	// return []func() datasource.DataSource{
-	// 		NewFeilongDataSource,
-	// }
-	return nil
+	return []func() datasource.DataSource{
+		NewFeilongDiskPoolDataSource,
+		NewFeilongFCPTemplatesDataSource,
+		NewFeilongGuestConsoleDataSource,
+		NewFeilongGuestDataSource,
+		NewFeilongGuestDirectoryDataSource,
+		NewFeilongGuestsDataSource,
+		NewFeilongGuestsStatsDataSource,
+		NewFeilongHostDataSource,
+		NewFeilongImagesDataSource,
+		NewFeilongSMAPIHealthDataSource,
+		NewFeilongVolumeConnectorDataSource,
+		NewFeilongVSwitchesDataSource,
+	}
 }
 
 func (p *FeilongProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewFeilongCloudinitParams,
+		NewFeilongFCPTemplate,
 		NewFeilongGuest,
+		NewFeilongGuestCapture,
+		NewFeilongGuestDisk,
+		NewFeilongGuestNIC,
+		NewFeilongImage,
+		NewFeilongImageExport,
+		NewFeilongVolumeAttachment,
 		NewFeilongVSwitch,
+		NewFeilongVSwitchGrant,
 	}
 }
 
@@ -132,3 +256,137 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
+
+// For internal use
+
+// Get a setting from the configuration, else from an environment variable, else from the credentials
+func configValue(value types.String, variable string, credentials map[string]string) string {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueString()
+	}
+	if env := os.Getenv(variable); env != "" {
+		return env
+	}
+	return credentials[strings.ToLower(strings.TrimPrefix(variable, "FEILONG_"))]
+}
+
+// Read a profile from a credentials file like:
+//   [default]
+//   connector = http://feilong.example.org
+//   admin_token = zvX2mFxuj8HcrYkAacLReV0RTQ0K5IIEighOR9F8AG
+//   local_user = johndoe@client.example.org
+// A missing default file or default profile is not an error
+func readCredentials(file string, profile string) (map[string]string, error) {
+	explicit := file != "" || profile != ""
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil
+		}
+		file = filepath.Join(home, ".config", "feilong", "credentials")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	f, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var credentials map[string]string
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line) - 1])
+			if section == profile && credentials == nil {
+				credentials = map[string]string {}
+			}
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("%s:%d: expected \"key = value\"", file, n)
+		}
+		if section == profile {
+			credentials[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), "\"")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if credentials == nil && explicit {
+		return nil, fmt.Errorf("profile \"%s\" not found in %s", profile, file)
+	}
+	return credentials, nil
+}
+
+// Build a TLS configuration, or return nil if there is nothing to set up
+func buildTLSConfig(caCertFile string, caCertPEM string, clientCert string, clientKey string, insecureSkipVerify bool) (*tls.Config, error) {
+	if caCertFile == "" && caCertPEM == "" && clientCert == "" && clientKey == "" && !insecureSkipVerify {
+		return nil, nil
+	}
+	config := tls.Config {
+		InsecureSkipVerify:	insecureSkipVerify,
+	}
+
+	// Trust additional CAs
+	if caCertFile != "" || caCertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if caCertFile != "" {
+			pem, err := os.ReadFile(caCertFile)
+			if err != nil {
+				return nil, err
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no CA certificate found in %s", caCertFile)
+			}
+		}
+		if caCertPEM != "" && !pool.AppendCertsFromPEM([]byte(caCertPEM)) {
+			return nil, fmt.Errorf("no CA certificate found in ca_cert_pem")
+		}
+		config.RootCAs = pool
+	}
+
+	// Present a client certificate
+	if clientCert != "" || clientKey != "" {
+		if clientCert == "" || clientKey == "" {
+			return nil, fmt.Errorf("client_cert and client_key must be set together")
+		}
+		certPEM, err := pemContents(clientCert)
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := pemContents(clientKey)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate { cert }
+	}
+
+	return &config, nil
+}
+
+// Get PEM data given either inline or as a path
+func pemContents(value string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}